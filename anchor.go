@@ -0,0 +1,84 @@
+package gmir
+
+import (
+	"github.com/gdamore/tcell/v2"
+)
+
+// An Anchor describes a scroll position by the content around it. This
+// allows restoring the position in a changed version of the document.
+type Anchor struct {
+	// The text of the nearest heading at or above the first displayed
+	// line. Empty, if there is no such heading.
+	Heading string `json:"heading"`
+
+	// The number of lines between the heading and the first displayed
+	// line.
+	Distance int `json:"distance"`
+
+	Text       string `json:"text"` // The text of the first displayed line.
+	Line       int    `json:"line"` // The index of the first displayed line.
+	LineOffset int    `json:"lineOffset"`
+}
+
+// Anchor returns the anchor of the current scroll position of v.
+func (v View) Anchor() Anchor {
+	a := Anchor{Line: v.line, LineOffset: v.lineOffset}
+	if v.IsEmpty() {
+		return a
+	}
+	a.Text = v.lines[v.line].Text()
+	for i := v.line; i >= 0; i-- {
+		if isHeading(v.lines[i]) {
+			a.Heading = v.lines[i].Text()
+			a.Distance = v.line - i
+			break
+		}
+	}
+	return a
+}
+
+// ScrollToAnchor scrolls to the position described by a. The position
+// is searched relative to the heading of a, if it still exists. If a
+// line with the text of a is found near the position, it is used
+// instead.
+func (v *View) ScrollToAnchor(screen tcell.Screen, a Anchor) {
+	if v.IsEmpty() {
+		return
+	}
+	start, end, target := 0, len(v.lines), a.Line
+	if heading := v.nearestLineWithText(a.Heading, 0, len(v.lines), a.Line-a.Distance); heading >= 0 {
+		start, target = heading, heading+a.Distance
+		end = heading + 1
+		for end < len(v.lines) && !isHeading(v.lines[end]) {
+			end++
+		}
+	}
+	if line := v.nearestLineWithText(a.Text, start, end, target); line >= 0 {
+		v.ScrollToPosition(screen, line, a.LineOffset)
+	} else {
+		v.ScrollToPosition(screen, target, 0)
+	}
+}
+
+// nearestLineWithText returns the index of the line with the given text,
+// that is closest to target and within start (inclusive) and end
+// (exclusive). Returns -1, if there is no such line or text is empty.
+func (v View) nearestLineWithText(text string, start, end, target int) int {
+	if text == "" {
+		return -1
+	}
+	nearest := -1
+	for i := start; i < end; i++ {
+		if v.lines[i].Text() == text && (nearest < 0 || abs(i-target) < abs(nearest-target)) {
+			nearest = i
+		}
+	}
+	return nearest
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
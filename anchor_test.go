@@ -0,0 +1,53 @@
+package gmir
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+const anchorDoc = "# One\na\nb\nc\n# Two\nd\ne\nf\n"
+
+// anchorTestCases scroll anchorDoc to line, take the anchor and restore
+// it in changed. expected is the text of the first displayed line.
+var anchorTestCases = []struct {
+	name     string
+	line     int
+	changed  string
+	expected string
+}{
+	{"unchanged", 6, anchorDoc, "e"},
+	{"lines added above the heading", 6, "new\nnew\n" + anchorDoc, "e"},
+	{"lines added below the heading", 6, "# One\na\nb\nc\n# Two\nnew\nd\ne\nf\n", "e"},
+	{"line removed", 6, "# One\na\nb\nc\n# Two\nd\nf\n", "f"},
+	{"heading renamed", 6, "# One\na\nb\nc\n# Second\nd\ne\nf\n", "e"},
+	{"heading removed", 2, "a\nb\nc\n", "b"},
+	{"text in another section", 1, "# One\nx\n# Two\na\n", "x"},
+	{"heading line", 4, "new\n" + anchorDoc, "# Two"},
+	{"document shortened", 7, "# One\n", "# One"},
+}
+
+func TestScrollToAnchor(t *testing.T) {
+	s := tcell.NewSimulationScreen("")
+	if err := s.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Fini()
+	s.SetSize(80, 5)
+	for _, testCase := range anchorTestCases {
+		v, err := NewView(strings.NewReader(anchorDoc), "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		v.ScrollToPosition(s, testCase.line, 0)
+		changed, err := NewView(strings.NewReader(testCase.changed), "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		changed.ScrollToAnchor(s, v.Anchor())
+		if got := changed.lines[changed.line].Text(); got != testCase.expected {
+			t.Errorf("Got '%s' for %s, expected '%s'.", got, testCase.name, testCase.expected)
+		}
+	}
+}
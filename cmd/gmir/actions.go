@@ -0,0 +1,232 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/codesoap/gmir"
+	"github.com/gdamore/tcell/v2"
+)
+
+// An action is something, that can be triggered by a key binding.
+type action struct {
+	name string
+	help string
+	run  func(vs *views, s tcell.Screen)
+}
+
+// actions contains all actions in the order they are listed in the
+// usage info.
+var actions = []action{
+	{"scroll-up", "Scroll up one line", func(vs *views, s tcell.Screen) {
+		vs.activeView().Scroll(s, 1)
+	}},
+	{"scroll-down", "Scroll down one line", func(vs *views, s tcell.Screen) {
+		vs.activeView().Scroll(s, -1)
+	}},
+	{"scroll-right", "Scroll right one column", func(vs *views, s tcell.Screen) {
+		vs.activeView().ColOffset += 1
+	}},
+	{"half-page-up", "Scroll up half a page", func(vs *views, s tcell.Screen) {
+		_, height := s.Size()
+		vs.activeView().Scroll(s, height/2)
+	}},
+	{"half-page-down", "Scroll down half a page", func(vs *views, s tcell.Screen) {
+		_, height := s.Size()
+		vs.activeView().Scroll(s, -height/2)
+	}},
+	{"page-up", "Scroll up a full page", func(vs *views, s tcell.Screen) {
+		_, height := s.Size()
+		vs.activeView().Scroll(s, height-1)
+	}},
+	{"page-down", "Scroll down a full page", func(vs *views, s tcell.Screen) {
+		_, height := s.Size()
+		vs.activeView().Scroll(s, -height+1)
+	}},
+	{"top", "Go to the top", func(vs *views, s tcell.Screen) {
+		vs.activeView().ScrollToTop(s)
+	}},
+	{"bottom", "Go to the bottom", func(vs *views, s tcell.Screen) {
+		vs.activeView().ScrollToBottom(s)
+	}},
+	{"next-heading", "Go to next heading", func(vs *views, s tcell.Screen) {
+		vs.activeView().ScrollToNextHeading(s)
+	}},
+	{"prev-heading", "Go to previous heading", func(vs *views, s tcell.Screen) {
+		vs.activeView().ScrollToPrevHeading(s)
+	}},
+	{"next-paragraph", "Go to next paragraph", func(vs *views, s tcell.Screen) {
+		vs.activeView().ScrollToNextParagraph(s)
+	}},
+	{"prev-paragraph", "Go to previous paragraph", func(vs *views, s tcell.Screen) {
+		vs.activeView().ScrollToPrevParagraph(s)
+	}},
+	{"set-mark", "Set a mark named by the next letter", func(vs *views, s tcell.Screen) {
+		vs.pendingArg = func(vs *views, s tcell.Screen, r rune) {
+			v := vs.activeView()
+			if r == gmir.LastJumpMark || !gmir.IsMarkName(r) {
+				v.Info = "Invalid mark"
+				return
+			}
+			v.SetMark(r)
+		}
+	}},
+	{"jump-to-mark", "Jump to the mark named by the next letter; '' jumps back", func(vs *views, s tcell.Screen) {
+		vs.pendingArg = func(vs *views, s tcell.Screen, r rune) {
+			v := vs.activeView()
+			if !gmir.IsMarkName(r) {
+				v.Info = "Invalid mark"
+			} else if !v.JumpToMark(s, r) {
+				v.Info = "Mark not set"
+			}
+		}
+	}},
+	{"toc", "Show table of contents", func(vs *views, s tcell.Screen) {
+		if vs.toc.IsEmpty() {
+			vs.activeView().Info = "Table of contents is empty"
+		} else {
+			vs.showTOC = true
+		}
+	}},
+	{"search", "Start search", func(vs *views, s tcell.Screen) {
+		vs.activeView().StartSearch(gmir.Search, vs.searchEditor())
+	}},
+	{"reverse-search", "Start reverse search", func(vs *views, s tcell.Screen) {
+		vs.activeView().StartSearch(gmir.ReverseSearch, vs.searchEditor())
+	}},
+	{"next-match", "Go to next search match", func(vs *views, s tcell.Screen) {
+		v := vs.activeView()
+		if !v.ScrollDownToNextSearchMatch(s) {
+			v.Info = "No further match found."
+		}
+	}},
+	{"prev-match", "Go to previous search match", func(vs *views, s tcell.Screen) {
+		v := vs.activeView()
+		if !v.ScrollUpToNextSearchMatch(s) {
+			v.Info = "No previous match found."
+		}
+	}},
+	{"toggle-literal", "Toggle literal search; also works while typing a search term", func(vs *views, s tcell.Screen) {
+		v := vs.activeView()
+		v.ToggleLiteralSearch()
+		switch {
+		case v.Mode == gmir.Search || v.Mode == gmir.ReverseSearch:
+			v.UpdateSearch(s)
+		case v.LiteralSearch():
+			v.Info = "Searching for literal text"
+		default:
+			v.Info = "Searching for regular expressions"
+		}
+	}},
+	{"pick-link", "Pick a link by filtering", func(vs *views, s tcell.Screen) {
+		if vs.doc.LinkPickerView("").IsEmpty() {
+			vs.activeView().Info = "There are no links"
+			return
+		}
+		vs.activeView().ClearSelector()
+		vs.filter.Clear()
+		pickLinks(vs, s)
+	}},
+	{"select-by-selector", "Start typing a selector", func(vs *views, s tcell.Screen) {
+		vs.activeView().Mode = gmir.Select
+	}},
+	{"next-link", "Focus the next link", func(vs *views, s tcell.Screen) {
+		v := vs.activeView()
+		if !v.FocusNext(s) {
+			v.Info = "No link"
+		}
+	}},
+	{"prev-link", "Focus the previous link", func(vs *views, s tcell.Screen) {
+		v := vs.activeView()
+		if !v.FocusPrev(s) {
+			v.Info = "No link"
+		}
+	}},
+	{"select", "Select the focused link or table of contents entry", func(vs *views, s tcell.Screen) {
+		v := vs.activeView()
+		index, ok := v.Focus()
+		if !ok {
+			v.Info = "Nothing is focused"
+			return
+		}
+		v.ClearFocus()
+		selectEntry(vs, s, index)
+	}},
+	{"cancel", "Clear input, focus and right scroll or exit table of contents", func(vs *views, s tcell.Screen) {
+		v := vs.activeView()
+		v.ColOffset = 0
+		v.ClearSelector()
+		v.ClearFocus()
+		vs.showTOC = false
+	}},
+	{"hide-urls", "Hide link URLs", func(vs *views, s tcell.Screen) {
+		v := vs.activeView()
+		v.HideURLs()
+		v.FixLineOffset(s)
+	}},
+	{"show-urls", "Show link URLs", func(vs *views, s tcell.Screen) {
+		v := vs.activeView()
+		v.ShowURLs()
+		v.FixLineOffset(s)
+	}},
+	{"toggle-urls", "Toggle the display of link URLs", func(vs *views, s tcell.Screen) {
+		v := vs.activeView()
+		if v.URLsShown() {
+			v.HideURLs()
+		} else {
+			v.ShowURLs()
+		}
+		v.FixLineOffset(s)
+	}},
+	{"back", "Go back to the previous document", func(vs *views, s tcell.Screen) {
+		if vs.goBack() {
+			return
+		} else if oFlag == "json" {
+			// The browser, that runs gmir, may know the previous document.
+			exit(vs, s, newResult(vs, "back"))
+		}
+		vs.activeView().Info = "No previous document"
+	}},
+	{"forward", "Go forward to the next document", func(vs *views, s tcell.Screen) {
+		if !vs.goForward() {
+			vs.activeView().Info = "No next document"
+		}
+	}},
+	{"reload", "Reload the document from FILE", func(vs *views, s tcell.Screen) {
+		if vs.path == "" && oFlag == "json" {
+			// The browser, that runs gmir, may fetch the document again.
+			exit(vs, s, newResult(vs, "reload"))
+		}
+		if err := vs.reload(s); err != nil {
+			vs.activeView().Info = fmt.Sprint("Could not reload: ", err)
+		}
+	}},
+	{"follow", "Toggle reloading FILE whenever it changes", func(vs *views, s tcell.Screen) {
+		v := vs.activeView()
+		if vs.path == "" {
+			v.Info = "Cannot follow standard input"
+		} else if vs.following = !vs.following; vs.following {
+			v.Info = "Following changes"
+		} else {
+			v.Info = "Stopped following changes"
+		}
+	}},
+	{"command", "Type a command", func(vs *views, s tcell.Screen) {
+		v := vs.activeView()
+		v.ClearSelector()
+		vs.command.Clear()
+		v.Mode = gmir.Command
+		v.Prompt = &vs.command
+	}},
+	{"quit", "Quit", func(vs *views, s tcell.Screen) {
+		exit(vs, s, newResult(vs, "quit"))
+	}},
+}
+
+func findAction(name string) (action, bool) {
+	for _, a := range actions {
+		if a.name == name {
+			return a, true
+		}
+	}
+	return action{}, false
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/codesoap/gmir"
+	"github.com/codesoap/gmir/readline"
+	"github.com/gdamore/tcell/v2"
+)
+
+// A command is typed after ':'. Its arguments are separated by blanks,
+// except for the last one, which takes the rest of the line, so that it
+// may contain blanks.
+type command struct {
+	name    string
+	args    string // The arguments for the help, e.g. "LINE".
+	help    string
+	minArgs int
+	maxArgs int
+	run     func(vs *views, s tcell.Screen, args []string) error
+}
+
+var commands []command
+
+func init() {
+	// commands is initialized here, because the help command refers to
+	// it.
+	commands = []command{
+		{"goto", "LINE", "Go to line LINE of the source", 1, 1, func(vs *views, s tcell.Screen, args []string) error {
+			line, err := strconv.Atoi(args[0])
+			if err != nil || line < 1 {
+				return fmt.Errorf("invalid line '%s'", args[0])
+			}
+			vs.showTOC = false
+			vs.doc.ScrollToLine(s, line)
+			return nil
+		}},
+		{"heading", "TEXT", "Go to the first heading, that contains TEXT", 1, 1, func(vs *views, s tcell.Screen, args []string) error {
+			vs.showTOC = false
+			if !vs.doc.ScrollToHeading(s, args[0]) {
+				return fmt.Errorf("no heading contains '%s'", args[0])
+			}
+			return nil
+		}},
+		{"width", "COLUMNS", "Set the width, to which text is wrapped", 1, 1, func(vs *views, s tcell.Screen, args []string) error {
+			if err := setWidth(args); err != nil {
+				return err
+			}
+			vs.doc.FixLineOffset(s)
+			vs.toc.FixLineOffset(s)
+			return nil
+		}},
+		{"set", "[no]OPTION", "Switch " + optionNames() + " on or off", 1, 1, func(vs *views, s tcell.Screen, args []string) error {
+			name, on := strings.TrimPrefix(args[0], "no"), !strings.HasPrefix(args[0], "no")
+			set, ok := options[name]
+			if !ok {
+				return fmt.Errorf("unknown option '%s'", args[0])
+			}
+			return set(vs, s, on)
+		}},
+		{"open", "FILE", "Open the GMI file FILE", 1, 1, func(vs *views, s tcell.Screen, args []string) error {
+			if err := openFile(vs, s, args[0], fileURL(args[0])); err != nil {
+				return fmt.Errorf("could not open: %w", err)
+			}
+			return nil
+		}},
+		{"save", "FILE", "Save the document to FILE", 1, 1, func(vs *views, s tcell.Screen, args []string) error {
+			file, err := os.Create(args[0])
+			if err != nil {
+				return fmt.Errorf("could not save: %w", err)
+			}
+			if err = vs.doc.WriteSource(file); err != nil {
+				file.Close()
+				return fmt.Errorf("could not save: %w", err)
+			}
+			if err = file.Close(); err != nil {
+				return fmt.Errorf("could not save: %w", err)
+			}
+			vs.activeView().Info = fmt.Sprintf("Saved to %s", args[0])
+			return nil
+		}},
+		{"link", "NUMBER", "Select the link with the given number", 1, 1, func(vs *views, s tcell.Screen, args []string) error {
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n < 1 || n > vs.doc.LinkCount() {
+				return fmt.Errorf("there is no link %s", args[0])
+			}
+			vs.showTOC = false
+			followLink(vs, s, vs.doc.NthLink(n-1), n-1)
+			return nil
+		}},
+		{"help", "[COMMAND]", "Show the available commands or the help of COMMAND", 0, 1, func(vs *views, s tcell.Screen, args []string) error {
+			v := vs.activeView()
+			if len(args) == 0 {
+				names := make([]string, len(commands))
+				for i, c := range commands {
+					names[i] = c.name
+				}
+				v.Info = "Commands: " + strings.Join(names, ", ")
+				return nil
+			}
+			c, err := findCommand(args[0])
+			if err != nil {
+				return err
+			}
+			v.Info = fmt.Sprintf("%s %s: %s", c.name, c.args, c.help)
+			return nil
+		}},
+	}
+}
+
+// options contains the options of the set command by name. They are
+// switched off by prefixing their name with "no".
+var options = map[string]func(vs *views, s tcell.Screen, on bool) error{
+	"urls": func(vs *views, s tcell.Screen, on bool) error {
+		v := vs.activeView()
+		if on {
+			v.ShowURLs()
+		} else {
+			v.HideURLs()
+		}
+		v.FixLineOffset(s)
+		return nil
+	},
+	"ignorecase": func(vs *views, s tcell.Screen, on bool) error {
+		gmir.SetIgnoreCase(on)
+		return nil
+	},
+	"follow": func(vs *views, s tcell.Screen, on bool) error {
+		if on && vs.path == "" {
+			return fmt.Errorf("cannot follow standard input")
+		}
+		vs.following = on
+		return nil
+	},
+}
+
+// optionNames returns an enumeration of the names of options for the
+// help.
+func optionNames() string {
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names[:len(names)-1], ", ") + " or " + names[len(names)-1]
+}
+
+// findCommand returns the command, whose name is name or starts with
+// name, if there is only one such command.
+func findCommand(name string) (command, error) {
+	var found []command
+	for _, c := range commands {
+		if c.name == name {
+			return c, nil
+		} else if strings.HasPrefix(c.name, name) {
+			found = append(found, c)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return command{}, fmt.Errorf("unknown command '%s'", name)
+	case 1:
+		return found[0], nil
+	}
+	return command{}, fmt.Errorf("ambiguous command '%s'", name)
+}
+
+// parseCommand splits line into a command and its arguments.
+func parseCommand(line string) (command, []string, error) {
+	name, rest := cutField(line)
+	c, err := findCommand(name)
+	if err != nil {
+		return command{}, nil, err
+	}
+	var args []string
+	for rest != "" && len(args) < c.maxArgs-1 {
+		var arg string
+		arg, rest = cutField(rest)
+		args = append(args, arg)
+	}
+	if rest != "" {
+		if c.maxArgs == 0 {
+			return command{}, nil, fmt.Errorf("'%s' takes no arguments", c.name)
+		}
+		args = append(args, rest)
+	}
+	if len(args) < c.minArgs {
+		return command{}, nil, fmt.Errorf("usage: %s %s", c.name, c.args)
+	}
+	return c, args, nil
+}
+
+// cutField returns the first blank separated field of s and the rest of
+// s without surrounding blanks.
+func cutField(s string) (field, rest string) {
+	s = strings.TrimSpace(s)
+	end := strings.IndexFunc(s, unicode.IsSpace)
+	if end < 0 {
+		return s, ""
+	}
+	return s[:end], strings.TrimSpace(s[end:])
+}
+
+// runCommand runs the command typed as line. Errors are displayed in
+// the bar.
+func runCommand(vs *views, s tcell.Screen, line string) {
+	c, args, err := parseCommand(line)
+	if err == nil {
+		err = c.run(vs, s, args)
+	}
+	if err != nil {
+		msg := err.Error()
+		first, size := utf8.DecodeRuneInString(msg)
+		vs.activeView().Info = string(unicode.ToUpper(first)) + msg[size:]
+	}
+}
+
+// completeCommand completes the name of the command, that is being
+// typed in e, as far as it is unambiguous.
+func completeCommand(e *readline.Editor) {
+	input := e.Input()
+	if strings.IndexFunc(input, unicode.IsSpace) >= 0 {
+		return
+	}
+	var completion string
+	matches := 0
+	for _, c := range commands {
+		if !strings.HasPrefix(c.name, input) {
+			continue
+		}
+		if matches == 0 {
+			completion = c.name
+		} else {
+			completion = commonPrefix(completion, c.name)
+		}
+		matches++
+	}
+	if matches == 1 {
+		e.SetInput(completion + " ")
+	} else if matches > 1 {
+		e.SetInput(completion)
+	}
+}
+
+func commonPrefix(a, b string) string {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return a[:i]
+}
+
+// commandsHelp returns a description of all commands.
+func commandsHelp() string {
+	width := 0
+	for _, c := range commands {
+		if w := utf8.RuneCountInString(c.name + " " + c.args); w > width {
+			width = w
+		}
+	}
+	var help strings.Builder
+	for _, c := range commands {
+		usage := c.name + " " + c.args
+		padding := strings.Repeat(" ", width-utf8.RuneCountInString(usage))
+		fmt.Fprintf(&help, ":%s%s: %s\n", usage, padding, c.help)
+	}
+	return strings.TrimSuffix(help.String(), "\n")
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/codesoap/gmir/readline"
+)
+
+var findCommandTestCases = []struct {
+	name     string
+	expected string // The name of the found command; "" for an error.
+}{
+	{"goto", "goto"},
+	{"go", "goto"},
+	{"g", "goto"},
+	{"hel", "help"},
+	{"he", ""}, // Ambiguous between heading and help.
+	{"h", ""},
+	{"", ""},
+	{"gotox", ""},
+	{"x", ""},
+}
+
+func TestFindCommand(t *testing.T) {
+	for _, testCase := range findCommandTestCases {
+		c, err := findCommand(testCase.name)
+		if testCase.expected == "" && err == nil {
+			t.Errorf("Got '%s' for '%s', expected an error.", c.name, testCase.name)
+		} else if testCase.expected != "" && (err != nil || c.name != testCase.expected) {
+			t.Errorf("Got '%s' (%v) for '%s', expected '%s'.", c.name, err, testCase.name, testCase.expected)
+		}
+	}
+}
+
+var parseCommandTestCases = []struct {
+	line     string
+	expected string // The command and its arguments separated by '|'; "" for an error.
+}{
+	{"none", "none"},
+	{"none arg", ""},
+	{"two", ""},
+	{"two a", ""},
+	{"two a b", "two|a|b"},
+	{"  two   a  b  ", "two|a|b"},
+	{"two a b c", "two|a|b|c"},
+	{"two a b c d", "two|a|b|c d"}, // The last argument takes the rest of the line.
+	{"tw a b", "two|a|b"},
+	{"t a b", ""},
+	{"unknown", ""},
+}
+
+func TestParseCommand(t *testing.T) {
+	defer func(c []command) { commands = c }(commands)
+	commands = []command{
+		{name: "none"},
+		{name: "two", args: "A B [C]", minArgs: 2, maxArgs: 3},
+		{name: "three", args: "A B C", minArgs: 3, maxArgs: 3},
+	}
+	for _, testCase := range parseCommandTestCases {
+		c, args, err := parseCommand(testCase.line)
+		got := ""
+		if err == nil {
+			got = strings.Join(append([]string{c.name}, args...), "|")
+		}
+		if got != testCase.expected {
+			t.Errorf("Got '%s' (%v) for '%s', expected '%s'.", got, err, testCase.line, testCase.expected)
+		}
+	}
+}
+
+var cutFieldTestCases = []struct {
+	s, field, rest string
+}{
+	{"", "", ""},
+	{"foo", "foo", ""},
+	{"foo bar", "foo", "bar"},
+	{"  foo \t bar  baz ", "foo", "bar  baz"},
+}
+
+func TestCutField(t *testing.T) {
+	for _, testCase := range cutFieldTestCases {
+		field, rest := cutField(testCase.s)
+		if field != testCase.field || rest != testCase.rest {
+			t.Errorf("Got '%s' and '%s' for '%s', expected '%s' and '%s'.",
+				field, rest, testCase.s, testCase.field, testCase.rest)
+		}
+	}
+}
+
+var completeCommandTestCases = []struct {
+	input, expected string
+}{
+	{"go", "goto "},
+	{"h", "he"},
+	{"hel", "help "},
+	{"help", "help "},
+	{"x", "x"},
+	{"goto 1", "goto 1"},
+}
+
+func TestCompleteCommand(t *testing.T) {
+	for _, testCase := range completeCommandTestCases {
+		var e readline.Editor
+		e.SetInput(testCase.input)
+		completeCommand(&e)
+		if got := e.Input(); got != testCase.expected {
+			t.Errorf("Got '%s' for '%s', expected '%s'.", got, testCase.input, testCase.expected)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/codesoap/gmir"
+	"github.com/codesoap/gmir/selector"
+	"github.com/gdamore/tcell/v2"
+)
+
+// directives contains the handlers for all statements of the config
+// file by their first word.
+var directives = map[string]func(args []string) error{
+	"width":     setWidth,
+	"style":     setStyle,
+	"bind":      bindKeys,
+	"unbind":    unbindKeys,
+	"selectors": setSelectors,
+}
+
+// configPath returns the path of the config file, which is located in
+// $XDG_CONFIG_HOME/gmir or ~/.config/gmir.
+func configPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "gmir", "config")
+}
+
+// statePath returns the path of the file with the given name, in which
+// state is kept across sessions. It is located in $XDG_STATE_HOME/gmir
+// or ~/.local/state/gmir. Returns an empty string, if there is no home
+// directory.
+func statePath(name string) string {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "gmir", name)
+}
+
+// loadConfig applies the config file at path. A missing file is not an
+// error. Lines are made up of a directive followed by its arguments,
+// separated by blanks. Empty lines and lines starting with '#' are
+// ignored.
+func loadConfig(path string) error {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer file.Close()
+	s := bufio.NewScanner(file)
+	for number := 1; s.Scan(); number++ {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		directive, ok := directives[fields[0]]
+		if !ok {
+			return fmt.Errorf("%s:%d: unknown directive '%s'", path, number, fields[0])
+		}
+		if err := directive(fields[1:]); err != nil {
+			return fmt.Errorf("%s:%d: %v", path, number, err)
+		}
+	}
+	return s.Err()
+}
+
+// setWidth handles "width COLUMNS".
+func setWidth(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected one argument to 'width'")
+	}
+	width, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid width '%s'", args[0])
+	}
+	return gmir.SetMaxTextWidth(width)
+}
+
+// setSelectors handles "selectors digits" and "selectors letters
+// [ALPHABET]". Without ALPHABET, the keys of the home row are used.
+func setSelectors(args []string) error {
+	switch {
+	case len(args) == 1 && args[0] == "digits":
+		gmir.SetSelectors(selector.Decimal{})
+	case len(args) == 1 && args[0] == "letters":
+		gmir.SetSelectors(selector.HomeRow)
+	case len(args) == 2 && args[0] == "letters":
+		alphabet, err := selector.NewAlphabet(args[1])
+		if err != nil {
+			return err
+		}
+		gmir.SetSelectors(alphabet)
+	default:
+		return fmt.Errorf("expected 'digits' or 'letters [ALPHABET]' after 'selectors'")
+	}
+	return nil
+}
+
+// setStyle handles "style NAME [ATTRIBUTE]...".
+func setStyle(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("expected a style name")
+	}
+	style, err := parseStyle(args[1:])
+	if err != nil {
+		return err
+	}
+	return gmir.SetStyle(args[0], style)
+}
+
+// parseStyle returns the style made up of the given attributes. The
+// attributes are fg=COLOR, bg=COLOR, bold, dim, italic, underline and
+// reverse. Colors are given as names, like "blue", as palette numbers or
+// as "#rrggbb".
+func parseStyle(attrs []string) (tcell.Style, error) {
+	style := tcell.StyleDefault
+	for _, attr := range attrs {
+		switch attr {
+		case "bold":
+			style = style.Bold(true)
+		case "dim":
+			style = style.Dim(true)
+		case "italic":
+			style = style.Italic(true)
+		case "underline":
+			style = style.Underline(true)
+		case "reverse":
+			style = style.Reverse(true)
+		default:
+			key, value, found := strings.Cut(attr, "=")
+			if !found || key != "fg" && key != "bg" {
+				return style, fmt.Errorf("unknown style attribute '%s'", attr)
+			}
+			color, err := parseColor(value)
+			if err != nil {
+				return style, err
+			}
+			if key == "fg" {
+				style = style.Foreground(color)
+			} else {
+				style = style.Background(color)
+			}
+		}
+	}
+	return style, nil
+}
+
+func parseColor(name string) (tcell.Color, error) {
+	name = strings.ToLower(name)
+	if name == "default" {
+		return tcell.ColorDefault, nil
+	} else if n, err := strconv.Atoi(name); err == nil && n >= 0 && n < 256 {
+		return tcell.PaletteColor(n), nil
+	} else if color := tcell.GetColor(name); color != tcell.ColorDefault {
+		return color, nil
+	}
+	return tcell.ColorDefault, fmt.Errorf("unknown color '%s'", name)
+}
+
+// bindKeys handles "bind KEYS ACTION".
+func bindKeys(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("expected key sequence and action name")
+	}
+	return bind(args[0], args[1])
+}
+
+// unbindKeys handles "unbind KEYS".
+func unbindKeys(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected one key sequence")
+	}
+	return unbind(args[0])
+}
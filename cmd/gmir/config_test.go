@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/codesoap/gmir"
+	"github.com/codesoap/gmir/selector"
+	"github.com/gdamore/tcell/v2"
+)
+
+// The styles set by configTestCases are the default ones, so that other
+// tests are not affected. The expected error is prefixed with the path
+// of the config file.
+var configTestCases = []struct {
+	content  string
+	expected string // The expected error; "" if none.
+}{
+	{"", ""},
+	{"# A comment\n\n   \nwidth 60\nstyle bar reverse\n", ""},
+	{"width\n", ":1: expected one argument to 'width'"},
+	{"width 60 70\n", ":1: expected one argument to 'width'"},
+	{"\nwidth abc\n", ":2: invalid width 'abc'"},
+	{"width 0\n", ":1: text width must be positive"},
+	{"# A comment\ncolour text red\n", ":2: unknown directive 'colour'"},
+	{"style\n", ":1: expected a style name"},
+	{"style nosuch bold\n", ":1: unknown style 'nosuch'"},
+	{"style bar reverse\nstyle bar blink\n", ":2: unknown style attribute 'blink'"},
+	{"style bar fg\n", ":1: unknown style attribute 'fg'"},
+	{"style bar fg=nocolor\n", ":1: unknown color 'nocolor'"},
+	{"style bar size=3\n", ":1: unknown style attribute 'size=3'"},
+	{"width 60\nselectors hex\n", ":2: expected 'digits' or 'letters [ALPHABET]' after 'selectors'"},
+}
+
+func TestLoadConfig(t *testing.T) {
+	defer gmir.SetMaxTextWidth(72) // The default.
+	path := filepath.Join(t.TempDir(), "config")
+	for _, testCase := range configTestCases {
+		if err := os.WriteFile(path, []byte(testCase.content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		err := loadConfig(path)
+		if testCase.expected == "" && err != nil {
+			t.Errorf("Got error '%v' for %q.", err, testCase.content)
+		} else if testCase.expected != "" && fmt.Sprint(err) != path+testCase.expected {
+			t.Errorf("Got error '%v' for %q, expected '%s'.", err, testCase.content, path+testCase.expected)
+		}
+	}
+}
+
+func TestLoadMissingConfig(t *testing.T) {
+	if err := loadConfig(filepath.Join(t.TempDir(), "missing")); err != nil {
+		t.Errorf("Got error '%v' for a missing config file.", err)
+	}
+}
+
+var parseStyleTestCases = []struct {
+	attrs    []string
+	expected tcell.Style
+}{
+	{nil, tcell.StyleDefault},
+	{[]string{"bold", "underline"}, tcell.StyleDefault.Bold(true).Underline(true)},
+	{[]string{"dim", "italic", "reverse"}, tcell.StyleDefault.Dim(true).Italic(true).Reverse(true)},
+	{[]string{"fg=red"}, tcell.StyleDefault.Foreground(tcell.ColorRed)},
+	{[]string{"bg=208", "bold"}, tcell.StyleDefault.Background(tcell.PaletteColor(208)).Bold(true)},
+	{[]string{"fg=#ff8700", "bg=default"}, tcell.StyleDefault.Foreground(tcell.NewRGBColor(255, 135, 0))},
+}
+
+func TestParseStyle(t *testing.T) {
+	for _, testCase := range parseStyleTestCases {
+		style, err := parseStyle(testCase.attrs)
+		if err != nil {
+			t.Errorf("Got error '%v' for %v.", err, testCase.attrs)
+		} else if style != testCase.expected {
+			t.Errorf("Got %v for %v, expected %v.", style, testCase.attrs, testCase.expected)
+		}
+	}
+}
+
+var parseColorTestCases = []struct {
+	name     string
+	expected tcell.Color
+	ok       bool
+}{
+	{"red", tcell.ColorRed, true},
+	{"Navy", tcell.ColorNavy, true},
+	{"default", tcell.ColorDefault, true},
+	{"0", tcell.PaletteColor(0), true},
+	{"255", tcell.PaletteColor(255), true},
+	{"#ff8700", tcell.NewRGBColor(255, 135, 0), true},
+	{"#FF8700", tcell.NewRGBColor(255, 135, 0), true},
+	{"256", tcell.ColorDefault, false},
+	{"-1", tcell.ColorDefault, false},
+	{"#ggg", tcell.ColorDefault, false},
+	{"nocolor", tcell.ColorDefault, false},
+	{"", tcell.ColorDefault, false},
+}
+
+func TestParseColor(t *testing.T) {
+	for _, testCase := range parseColorTestCases {
+		color, err := parseColor(testCase.name)
+		if (err == nil) != testCase.ok || color != testCase.expected {
+			t.Errorf("Got %v (%v) for '%s', expected %v.", color, err, testCase.name, testCase.expected)
+		}
+	}
+}
+
+var setWidthTestCases = []struct {
+	args []string
+	ok   bool
+}{
+	{[]string{"80"}, true},
+	{[]string{"1"}, true},
+	{[]string{"-5"}, false},
+	{[]string{"wide"}, false},
+	{nil, false},
+	{[]string{"80", "90"}, false},
+}
+
+func TestSetWidth(t *testing.T) {
+	defer gmir.SetMaxTextWidth(72) // The default.
+	for _, testCase := range setWidthTestCases {
+		if err := setWidth(testCase.args); (err == nil) != testCase.ok {
+			t.Errorf("Got error '%v' for %v.", err, testCase.args)
+		}
+	}
+}
+
+var setSelectorsTestCases = []struct {
+	args  []string
+	runes string // The expected SelectorRunes; "" if args are invalid.
+}{
+	{[]string{"letters"}, "asdfjkl;"},
+	{[]string{"digits"}, "0-9"},
+	{[]string{"letters", "hjkl"}, "hjkl"},
+	{[]string{"letters", "h"}, ""},
+	{[]string{"letters", "hjkh"}, ""},
+	{[]string{"letters", "hj", "kl"}, ""},
+	{[]string{"hex"}, ""},
+	{nil, ""},
+}
+
+func TestSetSelectors(t *testing.T) {
+	defer gmir.SetSelectors(selector.Decimal{})
+	for _, testCase := range setSelectorsTestCases {
+		gmir.SetSelectors(selector.Decimal{})
+		err := setSelectors(testCase.args)
+		if testCase.runes == "" {
+			if err == nil {
+				t.Errorf("Got no error for %v.", testCase.args)
+			}
+		} else if err != nil {
+			t.Errorf("Got error '%v' for %v.", err, testCase.args)
+		} else if runes := gmir.SelectorRunes(); runes != testCase.runes {
+			t.Errorf("Got '%s' for %v, expected '%s'.", runes, testCase.args, testCase.runes)
+		}
+	}
+}
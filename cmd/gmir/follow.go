@@ -0,0 +1,82 @@
+package main
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// pollInterval is the time between two checks for changes of the
+// watched file.
+const pollInterval = 500 * time.Millisecond
+
+// eventFileChanged is posted, when the watched file has changed.
+type eventFileChanged struct {
+	tcell.EventTime
+	path string
+}
+
+// A watcher polls the modification time and size of a file to detect
+// changes. This works without OS specific APIs.
+type watcher struct {
+	mu   sync.Mutex
+	path string // The watched file; empty if nothing is watched.
+
+	// The path and file info found by the last check.
+	checkedPath string
+	checkedInfo os.FileInfo
+}
+
+// watch replaces the watched file with path. An empty path stops
+// watching.
+func (w *watcher) watch(path string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.path = path
+}
+
+func (w *watcher) watchedPath() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.path
+}
+
+// run posts an eventFileChanged to s, whenever the watched file
+// changes. It blocks, so it should be called in its own goroutine.
+func (w *watcher) run(s tcell.Screen) {
+	for range time.Tick(pollInterval) {
+		if path, changed := w.check(); changed {
+			ev := &eventFileChanged{path: path}
+			ev.SetEventNow()
+			s.PostEvent(ev)
+		}
+	}
+}
+
+// check returns the watched path and true, if the watched file has
+// changed since the last check. A file, that has just started to be
+// watched, has not changed.
+func (w *watcher) check() (string, bool) {
+	path := w.watchedPath()
+	info, err := os.Stat(path)
+	if err != nil {
+		// The file may be missing temporarily while it is being saved.
+		return path, false
+	}
+	changed := path == w.checkedPath && w.checkedInfo != nil &&
+		(!info.ModTime().Equal(w.checkedInfo.ModTime()) || info.Size() != w.checkedInfo.Size())
+	w.checkedPath, w.checkedInfo = path, info
+	return path, changed
+}
+
+// updateWatcher makes w watch the file of the current document, if
+// changes are followed.
+func updateWatcher(w *watcher, vs *views) {
+	if vs.following {
+		w.watch(vs.path)
+	} else {
+		w.watch("")
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestWatcher(t *testing.T) {
+	dir := t.TempDir()
+	path, other := filepath.Join(dir, "doc.gmi"), filepath.Join(dir, "other.gmi")
+	for _, p := range []string{path, other} {
+		if err := os.WriteFile(p, []byte("# Doc\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w := &watcher{}
+	check := func(expected bool, situation string) {
+		t.Helper()
+		if _, changed := w.check(); changed != expected {
+			t.Errorf("Got change %t %s, expected %t.", changed, situation, expected)
+		}
+	}
+	check(false, "without a watched file")
+	w.watch(path)
+	check(false, "after starting to watch")
+	check(false, "without modification")
+
+	if err := os.WriteFile(path, []byte("# Doc\nMore text.\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	check(true, "after changing the size")
+	check(false, "after the change was reported")
+
+	mtime := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+	check(true, "after changing the modification time")
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	check(false, "while the file is missing")
+
+	w.watch(other)
+	check(false, "after switching to another file")
+	w.watch("")
+	check(false, "after stopping to watch")
+}
+
+func TestReloadKeepsPosition(t *testing.T) {
+	s := tcell.NewSimulationScreen("")
+	if err := s.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Fini()
+	s.SetSize(80, 10)
+	var content strings.Builder
+	for i := 1; i <= 100; i++ {
+		fmt.Fprintf(&content, "line %d\n", i)
+	}
+	path := filepath.Join(t.TempDir(), "doc.gmi")
+	if err := os.WriteFile(path, []byte(content.String()), 0600); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := readView(path, "doc.gmi", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc.ScrollToPosition(s, 40, 0)
+	doc.SetMark('a')
+	vs := &views{}
+	vs.show(page{doc: doc, path: path})
+
+	changed := "# Added\nnew\nnew\n" + content.String()
+	if err = os.WriteFile(path, []byte(changed), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err = vs.reload(s); err != nil {
+		t.Fatal(err)
+	}
+	if text := vs.doc.Anchor().Text; text != "line 41" {
+		t.Errorf("Reloaded document is at '%s', expected 'line 41'.", text)
+	}
+	vs.doc.ScrollToTop(s)
+	if !vs.doc.JumpToMark(s, 'a') {
+		t.Errorf("Mark a was lost by reloading.")
+	} else if text := vs.doc.Anchor().Text; text != "line 41" {
+		t.Errorf("Mark a is at '%s', expected 'line 41'.", text)
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/codesoap/gmir"
+	"github.com/gdamore/tcell/v2"
+)
+
+// A page is a document within the navigation history. Because the
+// view is kept as a whole, the scroll position is restored when going
+// back or forward to a page.
+type page struct {
+	doc  gmir.View
+	path string // The file doc was read from; empty for standard input.
+	base string // The URL of doc; empty, if unknown.
+	key  string // The key of the reading position of doc; empty, if unknown.
+}
+
+func (vs *views) currentPage() page {
+	return page{doc: vs.doc, path: vs.path, base: vs.base, key: vs.key}
+}
+
+// show displays p, without touching the history. The reading position
+// of the current document is saved.
+func (vs *views) show(p page) {
+	if err := readingPositions.save(vs.key, vs.doc); err != nil {
+		p.doc.Info = fmt.Sprint("Could not save reading position: ", err)
+	}
+	vs.doc = p.doc
+	vs.path = p.path
+	vs.base = p.base
+	vs.key = p.key
+	vs.toc = p.doc.TOCView()
+	vs.showTOC = false
+	vs.showPicker = false
+}
+
+// open displays p and puts the current page onto the back stack. The
+// forward stack is discarded.
+func (vs *views) open(p page) {
+	vs.back = append(vs.back, vs.currentPage())
+	vs.forward = nil
+	vs.show(p)
+}
+
+// goBack displays the previous page. Returns false, if there is none.
+func (vs *views) goBack() bool {
+	if len(vs.back) == 0 {
+		return false
+	}
+	vs.forward = append(vs.forward, vs.currentPage())
+	p := vs.back[len(vs.back)-1]
+	vs.back = vs.back[:len(vs.back)-1]
+	vs.show(p)
+	return true
+}
+
+// goForward displays the page, that was left by going back. Returns
+// false, if there is none.
+func (vs *views) goForward() bool {
+	if len(vs.forward) == 0 {
+		return false
+	}
+	vs.back = append(vs.back, vs.currentPage())
+	p := vs.forward[len(vs.forward)-1]
+	vs.forward = vs.forward[:len(vs.forward)-1]
+	vs.show(p)
+	return true
+}
+
+// reload reads the current document from its file again. The scroll
+// position is anchored to the content, so that it is kept, even if
+// lines have been added or removed above it.
+func (vs *views) reload(s tcell.Screen) error {
+	if vs.path == "" {
+		return fmt.Errorf("cannot reload standard input")
+	}
+	doc, err := readView(vs.path, vs.doc.Title(), vs.base)
+	if err != nil {
+		return err
+	}
+	doc.ScrollToAnchor(s, vs.doc.Anchor())
+	doc.ColOffset = vs.doc.ColOffset
+	doc.SetMarks(vs.doc.Marks())
+	doc.TakeSearch(vs.doc)
+	showTOC := vs.showTOC
+	vs.show(page{doc: doc, path: vs.path, base: vs.base, key: vs.key})
+	vs.showTOC = showTOC && !vs.toc.IsEmpty()
+	return nil
+}
+
+// loadingView returns the view of the page, whose document is still
+// being loaded, or nil, if there is none.
+func (vs *views) loadingView() *gmir.View {
+	if vs.doc.Loading() {
+		return &vs.doc
+	}
+	for _, pages := range [][]page{vs.back, vs.forward} {
+		for i := range pages {
+			if pages[i].doc.Loading() {
+				return &pages[i].doc
+			}
+		}
+	}
+	return nil
+}
+
+// refreshTOC adds the headings to the table of contents, that have been
+// added to the document. Anything being typed in the table of contents
+// is kept.
+func (vs *views) refreshTOC() {
+	vs.doc.UpdateTOC(&vs.toc)
+}
+
+// readView reads the document at path. Its links are resolved against
+// base, unless base is empty.
+func readView(path, title, base string) (gmir.View, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return gmir.View{}, err
+	}
+	defer file.Close()
+	doc, err := gmir.NewView(file, title)
+	if err != nil || base == "" {
+		return doc, err
+	}
+	return doc, doc.SetBase(base)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/codesoap/gmir"
+	"github.com/gdamore/tcell/v2"
+)
+
+// testPage returns a page with a document titled title.
+func testPage(t *testing.T, title string) page {
+	t.Helper()
+	doc, err := gmir.NewView(strings.NewReader("# "+title+"\n"), title)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return page{doc: doc}
+}
+
+// The steps of historyTestCase are run on a views, that initially shows
+// the page "A". Each step is "back", "forward" or the title of a page to
+// open.
+var historyTestCases = []struct {
+	name     string
+	steps    []string
+	expected string // The title of the displayed page.
+	canBack  bool
+	canFwd   bool
+}{
+	{"initial", nil, "A", false, false},
+	{"open", []string{"B"}, "B", true, false},
+	{"back", []string{"B", "C", "back"}, "B", true, true},
+	{"back twice", []string{"B", "C", "back", "back"}, "A", false, true},
+	{"back at start", []string{"back"}, "A", false, false},
+	{"forward", []string{"B", "C", "back", "back", "forward"}, "B", true, true},
+	{"forward at end", []string{"B", "forward"}, "B", true, false},
+	{"open clears forward", []string{"B", "C", "back", "D"}, "D", true, false},
+	{"back after open", []string{"B", "C", "back", "D", "back"}, "B", true, true},
+}
+
+func TestHistory(t *testing.T) {
+	for _, testCase := range historyTestCases {
+		vs := &views{}
+		vs.show(testPage(t, "A"))
+		for _, step := range testCase.steps {
+			switch step {
+			case "back":
+				vs.goBack()
+			case "forward":
+				vs.goForward()
+			default:
+				vs.open(testPage(t, step))
+			}
+		}
+		if got := vs.doc.Title(); got != testCase.expected {
+			t.Errorf("Got page '%s' for %s, expected '%s'.", got, testCase.name, testCase.expected)
+		}
+		if canBack := len(vs.back) > 0; canBack != testCase.canBack {
+			t.Errorf("Got back history %t for %s, expected %t.", canBack, testCase.name, testCase.canBack)
+		}
+		if canFwd := len(vs.forward) > 0; canFwd != testCase.canFwd {
+			t.Errorf("Got forward history %t for %s, expected %t.", canFwd, testCase.name, testCase.canFwd)
+		}
+	}
+}
+
+func TestHistoryKeepsPosition(t *testing.T) {
+	s := tcell.NewSimulationScreen("")
+	if err := s.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Fini()
+	s.SetSize(80, 10)
+	doc, err := gmir.NewView(strings.NewReader(strings.Repeat("text\n", 50)), "A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc.ScrollToPosition(s, 20, 0)
+	vs := &views{}
+	vs.show(page{doc: doc})
+	vs.open(testPage(t, "B"))
+	vs.goBack()
+	if line, _ := vs.doc.Position(); line != 20 {
+		t.Errorf("Got line %d after going back, expected 20.", line)
+	}
+}
+
+func TestReload(t *testing.T) {
+	s := tcell.NewSimulationScreen("")
+	if err := s.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Fini()
+	vs := &views{}
+	vs.show(testPage(t, "A"))
+	if err := vs.reload(s); err == nil {
+		t.Errorf("Got no error when reloading standard input.")
+	}
+
+	path := filepath.Join(t.TempDir(), "doc.gmi")
+	if err := os.WriteFile(path, []byte("# Old\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := readView(path, "doc.gmi", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vs.open(page{doc: doc, path: path})
+	if err = os.WriteFile(path, []byte("# New\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err = vs.reload(s); err != nil {
+		t.Fatal(err)
+	}
+	vs.doc.Searchpattern = regexp.MustCompile("New")
+	if !vs.doc.ScrollDownToSearchMatch(s) {
+		t.Errorf("The reloaded document does not contain the new content.")
+	}
+	if len(vs.back) != 1 || len(vs.forward) != 0 {
+		t.Errorf("Reloading changed the history.")
+	}
+}
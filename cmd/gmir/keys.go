@@ -0,0 +1,402 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/codesoap/gmir"
+	"github.com/gdamore/tcell/v2"
+)
+
+/*
+Keys are written like in vim: Printable characters stand for
+themselves, while other keys and keys with modifiers are enclosed in
+angle brackets, e.g. "<Up>", "<C-f>" for Ctrl-f, "<M-b>" for Alt-b or
+"<S-Tab>" for Shift-Tab. A key sequence is written by concatenating its
+keys, e.g. "gg" or "<C-x>k".
+*/
+
+// A binding maps a key sequence in canonical notation to an action.
+type binding struct {
+	keys   string
+	action string
+}
+
+// bindings contains the active key bindings in the order they were
+// defined.
+var bindings = []binding{
+	{"<Up>", "scroll-up"},
+	{"k", "scroll-up"},
+	{"<Down>", "scroll-down"},
+	{"j", "scroll-down"},
+	{"<Right>", "scroll-right"},
+	{"l", "scroll-right"},
+	{"u", "half-page-up"},
+	{"d", "half-page-down"},
+	{"<PgUp>", "page-up"},
+	{"b", "page-up"},
+	{"<PgDn>", "page-down"},
+	{"f", "page-down"},
+	{"<Space>", "page-down"},
+	{"g", "top"},
+	{"G", "bottom"},
+	{"h", "next-heading"},
+	{"H", "prev-heading"},
+	{"s", "next-paragraph"},
+	{"S", "prev-paragraph"},
+	{"m", "set-mark"},
+	{"'", "jump-to-mark"},
+	{"t", "toc"},
+	{"/", "search"},
+	{"?", "reverse-search"},
+	{"n", "next-match"},
+	{"p", "prev-match"},
+	{"<C-r>", "toggle-literal"},
+	{"L", "pick-link"},
+	{"o", "select-by-selector"},
+	{"<Tab>", "next-link"},
+	{"<S-Tab>", "prev-link"},
+	{"<Enter>", "select"},
+	{"<Esc>", "cancel"},
+	{"v", "hide-urls"},
+	{"V", "show-urls"},
+	{"<BS>", "back"},
+	{"[", "back"},
+	{"]", "forward"},
+	{"r", "reload"},
+	{"F", "follow"},
+	{":", "command"},
+	{"q", "quit"},
+}
+
+// keyNames contains the names of special keys. Keys with the same
+// value, like tcell.KeyBackspace and tcell.KeyCtrlH, share the name.
+var keyNames = map[tcell.Key]string{
+	tcell.KeyUp:         "Up",
+	tcell.KeyDown:       "Down",
+	tcell.KeyLeft:       "Left",
+	tcell.KeyRight:      "Right",
+	tcell.KeyPgUp:       "PgUp",
+	tcell.KeyPgDn:       "PgDn",
+	tcell.KeyHome:       "Home",
+	tcell.KeyEnd:        "End",
+	tcell.KeyInsert:     "Insert",
+	tcell.KeyDelete:     "Del",
+	tcell.KeyEnter:      "Enter",
+	tcell.KeyTab:        "Tab",
+	tcell.KeyEsc:        "Esc",
+	tcell.KeyBackspace:  "BS",
+	tcell.KeyBackspace2: "BS",
+	tcell.KeyF1:         "F1",
+	tcell.KeyF2:         "F2",
+	tcell.KeyF3:         "F3",
+	tcell.KeyF4:         "F4",
+	tcell.KeyF5:         "F5",
+	tcell.KeyF6:         "F6",
+	tcell.KeyF7:         "F7",
+	tcell.KeyF8:         "F8",
+	tcell.KeyF9:         "F9",
+	tcell.KeyF10:        "F10",
+	tcell.KeyF11:        "F11",
+	tcell.KeyF12:        "F12",
+}
+
+// runeNames contains the names of printable characters, that cannot
+// stand for themselves.
+var runeNames = map[rune]string{
+	' ': "Space",
+	'<': "lt",
+	'>': "gt",
+}
+
+// keyName returns the canonical notation of the key of ev. Returns an
+// empty string for keys, that cannot be bound.
+func keyName(ev *tcell.EventKey) string {
+	mods := ev.Modifiers()
+	var name string
+	switch key := ev.Key(); {
+	case key == tcell.KeyRune:
+		// The shift modifier is already reflected in the rune.
+		mods &^= tcell.ModShift
+		name = runeName(ev.Rune())
+	case key == tcell.KeyBacktab:
+		mods |= tcell.ModShift
+		name = "Tab"
+	case keyNames[key] != "":
+		name = keyNames[key]
+		if key == tcell.KeyBackspace || key == tcell.KeyTab || key == tcell.KeyEnter || key == tcell.KeyEsc {
+			// These keys are reported like Ctrl-H, Ctrl-I, Ctrl-M and Ctrl-[.
+			mods &^= tcell.ModCtrl
+		}
+	case key >= tcell.KeyCtrlA && key <= tcell.KeyCtrlZ:
+		mods |= tcell.ModCtrl
+		name = string(rune('a' + key - tcell.KeyCtrlA))
+	default:
+		return ""
+	}
+	return formatKey(mods, name)
+}
+
+func runeName(r rune) string {
+	if name, ok := runeNames[r]; ok {
+		return name
+	}
+	return string(r)
+}
+
+func formatKey(mods tcell.ModMask, name string) string {
+	prefix := ""
+	if mods&tcell.ModCtrl != 0 {
+		prefix += "C-"
+	}
+	if mods&tcell.ModAlt != 0 {
+		prefix += "M-"
+	}
+	if mods&tcell.ModShift != 0 {
+		prefix += "S-"
+	}
+	if prefix == "" && utf8.RuneCountInString(name) == 1 {
+		return name
+	}
+	return "<" + prefix + name + ">"
+}
+
+// parseKeys converts the key sequence keys to canonical notation.
+func parseKeys(keys string) (string, error) {
+	if keys == "" {
+		return "", fmt.Errorf("empty key sequence")
+	}
+	var canonical string
+	for keys != "" {
+		if keys[0] != '<' {
+			r, size := utf8.DecodeRuneInString(keys)
+			canonical += formatKey(0, runeName(r))
+			keys = keys[size:]
+			continue
+		}
+		end := strings.IndexByte(keys, '>')
+		if end < 0 {
+			return "", fmt.Errorf("missing '>' in key sequence")
+		}
+		key, err := parseSpecialKey(keys[1:end])
+		if err != nil {
+			return "", err
+		}
+		canonical += key
+		keys = keys[end+1:]
+	}
+	return canonical, nil
+}
+
+// parseSpecialKey converts the content of angle brackets to canonical
+// notation.
+func parseSpecialKey(key string) (string, error) {
+	var mods tcell.ModMask
+	modNames, key := splitModifiers(key)
+	for _, mod := range modNames {
+		switch mod[0] {
+		case 'C', 'c':
+			mods |= tcell.ModCtrl
+		case 'M', 'm', 'A', 'a':
+			mods |= tcell.ModAlt
+		case 'S', 's':
+			mods |= tcell.ModShift
+		default:
+			return "", fmt.Errorf("unknown modifier '%s'", mod)
+		}
+	}
+	if utf8.RuneCountInString(key) == 1 {
+		if mods&tcell.ModCtrl != 0 {
+			key = strings.ToLower(key)
+		} else if mods&tcell.ModShift != 0 {
+			// Like keyName, which expects shift to be reflected in the rune.
+			mods &^= tcell.ModShift
+			key = strings.ToUpper(key)
+		}
+		r, _ := utf8.DecodeRuneInString(key)
+		return formatKey(mods, runeName(r)), nil
+	}
+	for _, name := range keyNames {
+		if strings.EqualFold(name, key) {
+			return formatKey(mods, name), nil
+		}
+	}
+	for _, name := range runeNames {
+		if strings.EqualFold(name, key) {
+			return formatKey(mods, name), nil
+		}
+	}
+	return "", fmt.Errorf("unknown key '%s'", key)
+}
+
+// splitKeys splits a key sequence in canonical notation into its keys.
+func splitKeys(keys string) []string {
+	split := make([]string, 0)
+	for keys != "" {
+		size := 0
+		if keys[0] == '<' {
+			size = strings.IndexByte(keys, '>') + 1
+		} else {
+			_, size = utf8.DecodeRuneInString(keys)
+		}
+		split = append(split, keys[:size])
+		keys = keys[size:]
+	}
+	return split
+}
+
+// displayNames contains the names of keys, as they are presented in
+// the usage info, if they differ from the canonical notation.
+var displayNames = map[string]string{
+	"C-":   "Ctrl-",
+	"M-":   "Alt-",
+	"S-":   "Shift-",
+	"BS":   "Backspace",
+	"PgUp": "Page up",
+	"PgDn": "Page down",
+	"Del":  "Delete",
+	"lt":   "<",
+	"gt":   ">",
+}
+
+// displayKeys returns a key sequence in canonical notation in the way
+// it is presented in the usage info, e.g. "Ctrl-f" instead of "<C-f>".
+func displayKeys(keys string) string {
+	split := splitKeys(keys)
+	simple := true
+	for i, key := range split {
+		if !strings.HasPrefix(key, "<") {
+			continue
+		}
+		simple = false
+		mods, name := splitModifiers(key[1 : len(key)-1])
+		display := ""
+		for _, mod := range mods {
+			display += displayNames[mod]
+		}
+		if n, ok := displayNames[name]; ok {
+			name = n
+		}
+		split[i] = display + name
+	}
+	if simple {
+		return strings.Join(split, "")
+	}
+	return strings.Join(split, " ")
+}
+
+// splitModifiers splits the content of angle brackets into modifiers,
+// like "C-", and the name of the key.
+func splitModifiers(key string) (mods []string, name string) {
+	for len(key) > 2 && key[1] == '-' {
+		mods = append(mods, key[:2])
+		key = key[2:]
+	}
+	return mods, key
+}
+
+// bind binds keys, given in any notation, to the action with the given
+// name. An existing binding for keys is replaced.
+func bind(keys, actionName string) error {
+	canonical, err := parseKeys(keys)
+	if err != nil {
+		return err
+	}
+	if _, ok := findAction(actionName); !ok {
+		return fmt.Errorf("unknown action '%s'", actionName)
+	}
+	for i, b := range bindings {
+		if b.keys == canonical {
+			bindings[i].action = actionName
+			return nil
+		}
+	}
+	bindings = append(bindings, binding{canonical, actionName})
+	return nil
+}
+
+// unbind removes the binding for keys, given in any notation.
+func unbind(keys string) error {
+	canonical, err := parseKeys(keys)
+	if err != nil {
+		return err
+	}
+	for i, b := range bindings {
+		if b.keys == canonical {
+			bindings = append(bindings[:i], bindings[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("'%s' is not bound", keys)
+}
+
+// boundAction returns the action bound to keys, which must be in
+// canonical notation.
+func boundAction(keys string) (action, bool) {
+	for _, b := range bindings {
+		if b.keys == keys {
+			return findAction(b.action)
+		}
+	}
+	return action{}, false
+}
+
+// isBindingPrefix returns true, if keys is the beginning of a longer
+// key sequence, that is bound.
+func isBindingPrefix(keys string) bool {
+	for _, b := range bindings {
+		if len(b.keys) > len(keys) && strings.HasPrefix(b.keys, keys) {
+			return true
+		}
+	}
+	return false
+}
+
+// keysOfAction returns the key sequences bound to the action with the
+// given name.
+func keysOfAction(name string) []string {
+	keys := make([]string, 0)
+	for _, b := range bindings {
+		if b.action == name {
+			keys = append(keys, b.keys)
+		}
+	}
+	return keys
+}
+
+// keyBindingsHelp returns a description of all active key bindings.
+func keyBindingsHelp() string {
+	type entry struct{ keys, help string }
+	entries := make([]entry, 0, len(actions))
+	for _, a := range actions {
+		keys := keysOfAction(a.name)
+		if len(keys) == 0 {
+			continue
+		}
+		display := make([]string, len(keys))
+		for i, k := range keys {
+			display[i] = displayKeys(k)
+		}
+		entries = append(entries, entry{strings.Join(display, ", "), a.help})
+	}
+	if gmir.DirectSelectors() {
+		entries = append(entries, entry{gmir.SelectorRunes(), "Select link or table of contents entry"})
+	} else if keys := keysOfAction("select-by-selector"); len(keys) > 0 {
+		// Letters are typed after starting a selector, because they may be
+		// bound to actions.
+		entries = append(entries, entry{displayKeys(keys[0]) + " " + gmir.SelectorRunes(), "Select link or table of contents entry"})
+	}
+	width := 0
+	for _, e := range entries {
+		if w := utf8.RuneCountInString(e.keys); w > width {
+			width = w
+		}
+	}
+	var help strings.Builder
+	for _, e := range entries {
+		padding := strings.Repeat(" ", width-utf8.RuneCountInString(e.keys))
+		fmt.Fprintf(&help, "%s%s: %s\n", e.keys, padding, e.help)
+	}
+	return strings.TrimSuffix(help.String(), "\n")
+}
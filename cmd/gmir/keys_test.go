@@ -0,0 +1,185 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/codesoap/gmir"
+	"github.com/gdamore/tcell/v2"
+)
+
+var parseKeysTestCases = []struct {
+	keys     string
+	expected string // "" for invalid notation.
+}{
+	{"j", "j"},
+	{"gg", "gg"},
+	{"G", "G"},
+	{" ", "<Space>"},
+	{"<C-f>", "<C-f>"},
+	{"<c-F>", "<C-f>"},
+	{"<M-b>", "<M-b>"},
+	{"<A-b>", "<M-b>"},
+	{"<S-a>", "A"},
+	{"<S-Tab>", "<S-Tab>"},
+	{"<C-M-Up>", "<C-M-Up>"},
+	{"<up>", "<Up>"},
+	{"<space>", "<Space>"},
+	{"<lt>", "<lt>"},
+	{"<bs>", "<BS>"},
+	{"<C-x>k", "<C-x>k"},
+	{"", ""},
+	{"<C-f", ""},
+	{"<", ""},
+	{"<X-f>", ""},
+	{"<Foo>", ""},
+}
+
+func TestParseKeys(t *testing.T) {
+	for _, testCase := range parseKeysTestCases {
+		got, err := parseKeys(testCase.keys)
+		if testCase.expected == "" && err == nil {
+			t.Errorf("Got '%s' for invalid '%s', expected an error.", got, testCase.keys)
+		} else if testCase.expected != "" && got != testCase.expected {
+			t.Errorf("Got '%s' (%v) for '%s', expected '%s'.", got, err, testCase.keys, testCase.expected)
+		}
+	}
+}
+
+var keyNameTestCases = []struct {
+	ev       *tcell.EventKey
+	expected string
+	display  string
+}{
+	{tcell.NewEventKey(tcell.KeyRune, 'j', tcell.ModNone), "j", "j"},
+	{tcell.NewEventKey(tcell.KeyRune, 'G', tcell.ModShift), "G", "G"},
+	{tcell.NewEventKey(tcell.KeyRune, ' ', tcell.ModNone), "<Space>", "Space"},
+	{tcell.NewEventKey(tcell.KeyRune, '<', tcell.ModNone), "<lt>", "<"},
+	{tcell.NewEventKey(tcell.KeyRune, 'b', tcell.ModAlt), "<M-b>", "Alt-b"},
+	{tcell.NewEventKey(tcell.KeyCtrlF, 0, tcell.ModCtrl), "<C-f>", "Ctrl-f"},
+	{tcell.NewEventKey(tcell.KeyBacktab, 0, tcell.ModNone), "<S-Tab>", "Shift-Tab"},
+	{tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), "<Enter>", "Enter"},
+	{tcell.NewEventKey(tcell.KeyBackspace2, 0, tcell.ModNone), "<BS>", "Backspace"},
+	{tcell.NewEventKey(tcell.KeyPgUp, 0, tcell.ModNone), "<PgUp>", "Page up"},
+	{tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModCtrl), "<C-Up>", "Ctrl-Up"},
+}
+
+// TestKeyName also checks, that the names of keys are valid notation,
+// that is parsed to the same name.
+func TestKeyName(t *testing.T) {
+	for _, testCase := range keyNameTestCases {
+		name := keyName(testCase.ev)
+		if name != testCase.expected {
+			t.Errorf("Got '%s', expected '%s'.", name, testCase.expected)
+			continue
+		}
+		if parsed, err := parseKeys(name); err != nil || parsed != name {
+			t.Errorf("Got '%s' (%v) when parsing '%s' again.", parsed, err, name)
+		}
+		if display := displayKeys(name); display != testCase.display {
+			t.Errorf("Got '%s' for displaying '%s', expected '%s'.", display, name, testCase.display)
+		}
+	}
+}
+
+func TestDisplayKeySequence(t *testing.T) {
+	if got := displayKeys("gg"); got != "gg" {
+		t.Errorf("Got '%s', expected 'gg'.", got)
+	}
+	if got := displayKeys("<C-x>k"); got != "Ctrl-x k" {
+		t.Errorf("Got '%s', expected 'Ctrl-x k'.", got)
+	}
+}
+
+// withBindings replaces the key bindings for the duration of a test.
+func withBindings(t *testing.T, b []binding) {
+	old := bindings
+	bindings = b
+	t.Cleanup(func() { bindings = old })
+}
+
+func TestBind(t *testing.T) {
+	withBindings(t, []binding{{"<C-f>", "page-down"}})
+	if err := bind("<c-F>", "page-up"); err != nil {
+		t.Fatal(err)
+	}
+	if len(bindings) != 1 || bindings[0].action != "page-up" {
+		t.Errorf("Binding equal keys did not replace the binding.")
+	}
+	if err := bind("gg", "top"); err != nil {
+		t.Fatal(err)
+	}
+	if a, ok := boundAction("gg"); !ok || a.name != "top" {
+		t.Errorf("Key sequence was not bound.")
+	}
+	if err := bind("x", "no-such-action"); err == nil {
+		t.Errorf("Got no error for an unknown action.")
+	}
+	if err := bind("<C-f", "top"); err == nil {
+		t.Errorf("Got no error for invalid keys.")
+	}
+	if err := unbind("<C-f>"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := boundAction("<C-f>"); ok {
+		t.Errorf("Unbound keys are still bound.")
+	}
+	if err := unbind("<C-f>"); err == nil {
+		t.Errorf("Got no error for unbinding keys, that are not bound.")
+	}
+}
+
+func TestBindingPrefix(t *testing.T) {
+	withBindings(t, []binding{{"g", "top"}, {"gx", "bottom"}, {"<C-x>k", "quit"}})
+	for _, keys := range []string{"g", "<C-x>"} {
+		if !isBindingPrefix(keys) {
+			t.Errorf("'%s' is not recognized as a prefix.", keys)
+		}
+	}
+	for _, keys := range []string{"gx", "x", "<C-x>k", "k"} {
+		if isBindingPrefix(keys) {
+			t.Errorf("'%s' is recognized as a prefix.", keys)
+		}
+	}
+	if _, ok := boundAction("<C-x>"); ok {
+		t.Errorf("A prefix, that is not bound itself, has an action.")
+	}
+}
+
+// keySequenceTestCases type keys on a document of 100 lines, which is
+// scrolled to line 50, with "g" bound to top, "gx" to bottom and "j" to
+// scroll-down.
+var keySequenceTestCases = []struct {
+	keys     string
+	expected int // The first displayed line.
+}{
+	{"g", 50},  // The sequence could be continued.
+	{"gx", 99}, // The longer sequence matches exactly.
+	{"gj", 1},  // "g" is run on its own, followed by "j".
+	{"jj", 52},
+	{"gz", 0}, // "z" is not bound.
+}
+
+func TestKeySequences(t *testing.T) {
+	withBindings(t, []binding{{"g", "top"}, {"gx", "bottom"}, {"j", "scroll-down"}})
+	s := tcell.NewSimulationScreen("")
+	if err := s.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Fini()
+	s.SetSize(80, 1)
+	for _, testCase := range keySequenceTestCases {
+		doc, err := gmir.NewView(strings.NewReader(strings.Repeat("text\n", 100)), "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		doc.ScrollToPosition(s, 50, 0)
+		vs := &views{doc: doc}
+		for _, r := range testCase.keys {
+			processKeyEvent(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone), vs, s)
+		}
+		if line, _ := vs.doc.Position(); line != testCase.expected {
+			t.Errorf("Got line %d after '%s', expected %d.", line, testCase.keys, testCase.expected)
+		}
+	}
+}
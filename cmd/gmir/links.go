@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/codesoap/gmir/parser"
+	"github.com/gdamore/tcell/v2"
+)
+
+// followLink opens the target of link within gmir, if it is a local
+// GMI file. Otherwise gmir quits and prints link. index is the index of
+// link within the current document.
+func followLink(vs *views, s tcell.Screen, link parser.LinkLine, index int) {
+	target := link.ResolvedURL()
+	if err := visitedLinks.Add(target); err != nil {
+		vs.activeView().Info = fmt.Sprint("Could not record visited link: ", err)
+	}
+	path, isLocal := localTarget(target)
+	if !isLocal || vs.path == "" {
+		exit(vs, s, followResult(vs, link, index))
+	}
+	if err := openFile(vs, s, path, target); err != nil {
+		vs.activeView().Info = fmt.Sprint("Could not open link: ", err)
+	}
+}
+
+// openFile opens the GMI file at path as a new page, whose links are
+// resolved against base. Its reading position is restored.
+func openFile(vs *views, s tcell.Screen, path, base string) error {
+	doc, err := readView(path, filepath.Base(path), base)
+	if err != nil {
+		return err
+	}
+	key := fileKey(path)
+	readingPositions.restore(key, s, &doc)
+	vs.open(page{doc: doc, path: path, base: base, key: key})
+	return nil
+}
+
+// followResult returns the result for following link, which has the
+// given index within the current document.
+func followResult(vs *views, link parser.LinkLine, index int) result {
+	r := newResult(vs, "follow")
+	r.URL = link.URL()
+	r.Resolved = link.ResolvedURL()
+	r.Label = link.Name()
+	r.Index = &index
+	return r
+}
+
+// baseURL returns the URL, that the links of the document read from
+// path are resolved against. This is the URL given with -b or the file
+// URL of path. Returns an empty string for standard input without -b.
+func baseURL(path string) string {
+	if bFlag != "" || path == "" {
+		return bFlag
+	}
+	return fileURL(path)
+}
+
+// fileURL returns the file URL of path.
+func fileURL(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
+
+// localTarget returns the path of the GMI file link points to. The
+// returned bool is false, if link is not a file URL of a local GMI
+// file.
+func localTarget(link string) (string, bool) {
+	u, err := url.Parse(link)
+	if err != nil || u.Scheme != "file" || u.Opaque != "" ||
+		u.Host != "" && u.Host != "localhost" {
+		return "", false
+	}
+	path := filepath.FromSlash(u.Path)
+	return path, isGMIFile(path)
+}
+
+func isGMIFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".gmi" || ext == ".gemini"
+}
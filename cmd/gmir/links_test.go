@@ -0,0 +1,120 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/codesoap/gmir"
+)
+
+var localTargetTestCases = []struct {
+	link string
+	path string // The expected path; "" if the link is not local.
+}{
+	{"file:///docs/about.gmi", "/docs/about.gmi"},
+	{"file:///docs/sub/page.gemini", "/docs/sub/page.gemini"},
+	{"file:///docs/PAGE.GMI", "/docs/PAGE.GMI"},
+	{"file:///a%20b/page.gmi", "/a b/page.gmi"},
+	{"file://localhost/abs/page.gmi", "/abs/page.gmi"},
+	{"file://host/abs/page.gmi", ""},
+	{"file:///docs/image.png", ""},
+	{"file:page.gmi", ""},
+	{"gemini://example.org/page.gmi", ""},
+	{"mailto:user@example.org", ""},
+	{"about.gmi", ""}, // Not resolved, because standard input has no location.
+	{"", ""},
+}
+
+func TestLocalTarget(t *testing.T) {
+	for _, testCase := range localTargetTestCases {
+		path, isLocal := localTarget(testCase.link)
+		expected := filepath.FromSlash(testCase.path)
+		if isLocal != (testCase.path != "") || isLocal && path != expected {
+			t.Errorf("Got '%s' (%t) for '%s', expected '%s'.",
+				path, isLocal, testCase.link, expected)
+		}
+	}
+}
+
+func TestFileURL(t *testing.T) {
+	wd, err := filepath.Abs(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "file://" + filepath.ToSlash(wd) + "/docs/index.gmi"
+	if got := fileURL(filepath.FromSlash("docs/index.gmi")); got != expected {
+		t.Errorf("Got '%s', expected '%s'.", got, expected)
+	}
+	if got := fileURL("/a b/c.gmi"); got != "file:///a%20b/c.gmi" {
+		t.Errorf("Got '%s', expected '%s'.", got, "file:///a%20b/c.gmi")
+	}
+}
+
+var baseURLTestCases = []struct {
+	bFlag, path string
+	expected    string // "FILE" for the file URL of path.
+}{
+	{"", "docs/index.gmi", "FILE"},
+	{"gemini://example.org/", "docs/index.gmi", "gemini://example.org/"},
+	{"gemini://example.org/", "", "gemini://example.org/"},
+	{"", "", ""},
+}
+
+func TestBaseURL(t *testing.T) {
+	defer func(b string) { bFlag = b }(bFlag)
+	for _, testCase := range baseURLTestCases {
+		bFlag = testCase.bFlag
+		expected := testCase.expected
+		if expected == "FILE" {
+			expected = fileURL(testCase.path)
+		}
+		if got := baseURL(testCase.path); got != expected {
+			t.Errorf("Got '%s' for '%s' with -b '%s', expected '%s'.",
+				got, testCase.path, testCase.bFlag, expected)
+		}
+	}
+}
+
+// linkTargetTestCases are links within the document at the relative
+// path docs/index.gmi.
+var linkTargetTestCases = []struct {
+	link string
+	path string // The expected path relative to the working directory; "" if the link is not local.
+}{
+	{"about.gmi", "docs/about.gmi"},
+	{"../up.gmi", "up.gmi"},
+	{"./sub/../page.gmi", "docs/page.gmi"},
+	{"image.png", ""},
+	{"gemini://example.org/page.gmi", ""},
+	{"//example.org/page.gmi", ""},
+	{"?query", "docs/index.gmi"},
+}
+
+func TestLinkTargetOfRelativeFile(t *testing.T) {
+	defer func(b string) { bFlag = b }(bFlag)
+	bFlag = ""
+	var input strings.Builder
+	for _, testCase := range linkTargetTestCases {
+		input.WriteString("=> " + testCase.link + "\n")
+	}
+	doc, err := gmir.NewView(strings.NewReader(input.String()), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.SetBase(baseURL(filepath.FromSlash("docs/index.gmi"))); err != nil {
+		t.Fatal(err)
+	}
+	wd, err := filepath.Abs(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, testCase := range linkTargetTestCases {
+		path, isLocal := localTarget(doc.NthLink(i).ResolvedURL())
+		expected := filepath.Join(wd, filepath.FromSlash(testCase.path))
+		if isLocal != (testCase.path != "") || isLocal && path != expected {
+			t.Errorf("Got '%s' (%t) for '%s', expected '%s'.",
+				path, isLocal, testCase.link, expected)
+		}
+	}
+}
@@ -1,88 +1,203 @@
 package main
 
 import (
+	"crypto/sha256"
 	"flag"
 	"fmt"
 	"io"
 	"os"
-	"regexp"
-	"strconv"
+	"strings"
 
 	"github.com/codesoap/gmir"
+	"github.com/codesoap/gmir/parser"
 	"github.com/codesoap/gmir/readline"
+	"github.com/codesoap/gmir/statefile"
+	"github.com/codesoap/gmir/visited"
 	"github.com/gdamore/tcell/v2"
 	_ "github.com/gdamore/tcell/v2/encoding"
 )
 
 var (
-	uFlag bool
-	tFlag string
+	aFlag    bool
+	fFlag    bool
+	uFlag    bool
+	tFlag    string
+	dumpFlag bool
+	ansiFlag bool
+	wFlag    int
+	oFlag    string
+	bFlag    string
+	iFlag    bool
+)
+
+var (
+	// visitedLinks contains the URLs of all links, that have been
+	// selected.
+	visitedLinks *visited.Store
+
+	// readingPositions contains the last reading position of documents.
+	readingPositions *positions
+
+	// stdinHash is the hash of the content read from standard input.
+	stdinHash = sha256.New()
+
+	// searchHistory is the file, in which search terms are kept.
+	searchHistory = statefile.New(statePath("search_history"), nil)
 )
 
 func showUsageInfo() {
 	fmt.Fprintln(flag.CommandLine.Output(), `Usage:
-gmir [-u] [-t TITLE] [FILE]
-If FILE is not given, standard input is read.
+gmir [-a] [-F] [-i] [-u] [-t TITLE] [-b BASE] [-o FORMAT] [FILE]
+gmir -dump [-ansi] [-w WIDTH] [-a] [-u] [-b BASE] [FILE]
+If FILE is not given, standard input is read and displayed while it is
+still loading. Links are resolved against BASE or, if FILE is given,
+against the location of FILE. Links to other local gmi files are
+opened in place, if FILE is given.
 
 Options:
+-a  Show only the alt text of preformatted blocks, that have one
+-F  Reload FILE whenever it changes
+-i  Ignore case when searching. By default, searches are only
+    case-sensitive, if the search term contains uppercase letters
 -u  Hide URLs of links by default
 -t  Set a title that is displayed in the bar.
+-b  Set the URL of the document, that links are resolved against
+-o  Set the output format to text or json. With text, the resolved URL
+    of the selected link is printed. With json, an object describing the
+    selected link or the action, that ended gmir, is printed
+-dump
+    Write the formatted document to standard output and exit
+-ansi
+    Style the output of -dump with ANSI escape sequences
+-w  Set the width of the output of -dump; defaults to 80
 
-Key bindings:
-Up, k     : Scroll up one line
-Down, j   : Scroll down one line
-Right, l  : Scroll right one column; reset with Esc
-u         : Scroll up half a page
-d         : Scroll down half a page
-Page up, b: Scroll up a full page
-Page down,
-f, Space  : Scroll down a full page
-g         : Go to the top
-G         : Go to the bottom
-h         : Go to next heading
-H         : Go to previous heading
-s         : Go to next paragraph
-S         : Go to previous paragraph
-t         : Show table of contents
-/         : Start search
-?         : Start reverse search
-n         : Go to next search match
-p         : Go to previous search match
-0-9       : Select link or table of contents entry
-Esc       : Clear input and right scroll or exit table of contents
-v         : Hide link URLs
-V         : Show link URLs
-q         : Quit`)
+Key bindings:`)
+	fmt.Fprintln(flag.CommandLine.Output(), keyBindingsHelp())
+	fmt.Fprintln(flag.CommandLine.Output(), "\nCommands:")
+	fmt.Fprintln(flag.CommandLine.Output(), commandsHelp())
 }
 
 type views struct {
 	doc     gmir.View // The main view.
 	toc     gmir.View // The view with the table of contents.
 	showTOC bool
+
+	picker     gmir.View // The view for picking a link by filtering.
+	showPicker bool
+
+	path    string // The file doc was read from; empty for standard input.
+	base    string // The URL of doc; empty, if unknown.
+	key     string // The key of the reading position of doc; empty, if unknown.
+	back    []page // Previously displayed pages; the last one is the most recent.
+	forward []page // Pages left by going back; the last one is the next.
+
+	following bool // True, if the document is reloaded whenever its file changes.
+
+	// The keys typed so far, that are the beginning of a bound key
+	// sequence.
+	pendingKeys string
+
+	// If not nil, the next typed rune is passed to pendingArg instead of
+	// being processed as usual. Used by actions, that take an argument.
+	pendingArg func(vs *views, s tcell.Screen, r rune)
+
+	// The mouse buttons, that were pressed at the last mouse event.
+	mouseButtons tcell.ButtonMask
+
+	// The editors for the search terms of doc and toc, for the filter of
+	// picker and for commands. Each keeps its own history; only the one of
+	// docSearch is saved.
+	docSearch, tocSearch, filter, command readline.Editor
 }
 
 func (vs *views) activeView() *gmir.View {
+	if vs.showPicker {
+		return &vs.picker
+	}
 	if vs.showTOC {
 		return &vs.toc
 	}
 	return &vs.doc
 }
 
+// searchEditor returns the editor for the search term of the active
+// view.
+func (vs *views) searchEditor() *readline.Editor {
+	if vs.activeView() == &vs.toc {
+		return &vs.tocSearch
+	}
+	return &vs.docSearch
+}
+
 func init() {
 	flag.Usage = showUsageInfo
+	flag.BoolVar(&aFlag, "a", false, "Show only the alt text of preformatted blocks, that have one")
+	flag.BoolVar(&fFlag, "F", false, "Reload FILE whenever it changes")
 	flag.BoolVar(&uFlag, "u", false, "Hide URLs on link lines by default")
 	flag.StringVar(&tFlag, "t", "", "Set a title that is displayed in the bar")
-	flag.Parse()
+	flag.BoolVar(&dumpFlag, "dump", false, "Write the formatted document to standard output and exit")
+	flag.BoolVar(&ansiFlag, "ansi", false, "Use ANSI escape sequences for styling with -dump")
+	flag.IntVar(&wFlag, "w", 80, "Set the width of the output of -dump")
+	flag.BoolVar(&iFlag, "i", false, "Ignore case when searching")
+	flag.StringVar(&oFlag, "o", "text", "Set the output format to text or json")
+	flag.StringVar(&bFlag, "b", "", "Set the base URL, that links are resolved against")
 }
 
 func main() {
-	in := getInput()
-	defer in.Close()
-	doc, err := gmir.NewView(in, tFlag)
+	flag.Parse()
+	if err := loadConfig(configPath()); err != nil {
+		fmt.Fprintln(os.Stderr, "Could not load config:", err)
+		os.Exit(1)
+	}
+	flag.Parse() // After loading the config, which may change the usage info.
+	parser.AltTextOnly = aFlag
+	gmir.SetIgnoreCase(iFlag)
+	if len(flag.Args()) > 1 {
+		fmt.Fprintln(os.Stderr, "Too many arguments.")
+		os.Exit(1)
+	}
+	if oFlag != "text" && oFlag != "json" {
+		fmt.Fprintf(os.Stderr, "Unknown output format '%s'.\n", oFlag)
+		os.Exit(1)
+	}
+	if dumpFlag {
+		dump()
+		return
+	}
+	var err error
+	if visitedLinks, err = visited.Open(statePath("visited")); err != nil {
+		fmt.Fprintln(os.Stderr, "Could not load visited links:", err)
+		os.Exit(1)
+	}
+	gmir.SetVisited(visitedLinks.Contains)
+	if readingPositions, err = loadPositions(statePath("positions")); err != nil {
+		fmt.Fprintln(os.Stderr, "Could not load reading positions:", err)
+		os.Exit(1)
+	}
+	searchTerms, err := loadSearchHistory()
 	if err != nil {
-		fmt.Fprintln(os.Stderr, "Could not parse input:", err)
+		fmt.Fprintln(os.Stderr, "Could not load search history:", err)
 		os.Exit(1)
 	}
+
+	path := inputPath()
+	base := baseURL(path)
+	var doc gmir.View
+	if path == "" {
+		// Standard input is displayed while it is being read.
+		doc = gmir.NewLoadingView(tFlag)
+		if base != "" {
+			if err := doc.SetBase(base); err != nil {
+				fmt.Fprintln(os.Stderr, "Invalid base URL:", err)
+				os.Exit(1)
+			}
+		}
+	} else {
+		if doc, err = readView(path, tFlag, base); err != nil {
+			fmt.Fprintln(os.Stderr, "Could not read input:", err)
+			os.Exit(1)
+		}
+	}
 	if uFlag {
 		doc.HideURLs()
 	}
@@ -96,18 +211,57 @@ func main() {
 		fmt.Fprintf(os.Stderr, "%v\n", e)
 		os.Exit(1)
 	}
-	doc.Draw(s)
+	s.EnableMouse()
+	if path == "" {
+		go gmir.Load(io.TeeReader(os.Stdin, stdinHash), s)
+	}
 	vs := views{
-		doc: doc,
-		toc: doc.TOCView(),
+		doc:       doc,
+		path:      path,
+		base:      base,
+		following: fFlag && path != "",
 	}
+	vs.docSearch.SetHistory(searchTerms)
+	if path != "" {
+		vs.key = fileKey(path)
+		readingPositions.restore(vs.key, s, &vs.doc)
+	}
+	vs.toc = vs.doc.TOCView()
+	vs.doc.Draw(s)
+	w := &watcher{}
+	go w.run(s)
 	for {
+		updateWatcher(w, &vs)
 		processEvent(s.PollEvent(), &vs, s)
 		s.Clear()
 		vs.activeView().Draw(s)
 	}
 }
 
+// dump writes the formatted input to standard output.
+func dump() {
+	in := getInput()
+	defer in.Close()
+	doc, err := gmir.NewView(in, tFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Could not parse input:", err)
+		os.Exit(1)
+	}
+	if base := baseURL(inputPath()); base != "" {
+		if err := doc.SetBase(base); err != nil {
+			fmt.Fprintln(os.Stderr, "Invalid base URL:", err)
+			os.Exit(1)
+		}
+	}
+	if uFlag {
+		doc.HideURLs()
+	}
+	if err := doc.Dump(os.Stdout, wFlag, ansiFlag); err != nil {
+		fmt.Fprintln(os.Stderr, "Could not write output:", err)
+		os.Exit(1)
+	}
+}
+
 func processEvent(event tcell.Event, vs *views, s tcell.Screen) {
 	v := vs.activeView()
 	switch ev := event.(type) {
@@ -115,40 +269,69 @@ func processEvent(event tcell.Event, vs *views, s tcell.Screen) {
 		s.Sync()
 		vs.doc.FixLineOffset(s)
 		vs.toc.FixLineOffset(s)
+	case *eventFileChanged:
+		if vs.following && ev.path == vs.path {
+			if err := vs.reload(s); err != nil {
+				vs.activeView().Info = fmt.Sprint("Could not reload: ", err)
+			}
+		}
+	case *gmir.EventLoad:
+		loading := vs.loadingView()
+		if loading == nil {
+			return
+		}
+		err := loading.AddLoaded(ev)
+		if err == nil && !loading.Loading() && loading.IsEmpty() {
+			err = fmt.Errorf("given GMI is empty")
+		}
+		if err != nil {
+			s.Fini()
+			fmt.Fprintln(os.Stderr, "Could not parse input:", err)
+			os.Exit(1)
+		}
+		if loading == &vs.doc {
+			vs.refreshTOC()
+			if !loading.Loading() {
+				vs.key = contentKey(stdinHash)
+				if line, lineOffset := loading.Position(); line == 0 && lineOffset == 0 {
+					readingPositions.restore(vs.key, s, loading)
+				}
+			}
+		}
+	case *tcell.EventMouse:
+		processMouseEvent(ev, vs, s)
 	case *tcell.EventKey:
 		switch v.Mode {
 		case gmir.Regular:
 			processKeyEvent(ev, vs, s)
+		case gmir.Select:
+			processSelectorKey(ev, vs, s)
+		case gmir.Filter:
+			processFilterKey(ev, vs, s)
+		case gmir.Command:
+			processCommandKey(ev, vs, s)
 		case gmir.Search, gmir.ReverseSearch:
-			switch readline.ProcessKey(ev) {
+			// Only this action is available while typing, because the other
+			// keys are used for editing the search term.
+			if a, ok := boundAction(keyName(ev)); ok && a.name == "toggle-literal" {
+				a.run(vs, s)
+				return
+			}
+			prompt := v.Prompt
+			switch prompt.ProcessKey(ev) {
 			case readline.Reading:
-				v.Searchterm = readline.Input()
-				v.Cursor = readline.Cursor()
+				v.UpdateSearch(s)
 			case readline.Done:
-				v.Searchterm = ""
-				v.Cursor = 0
-				history, historyIndex := readline.History()
-				re, err := regexp.Compile(history[historyIndex])
-				if err != nil {
-					v.Info = "Invalid pattern"
-				} else {
-					v.Searchpattern = re
-					if v.Mode == gmir.ReverseSearch {
-						if !v.ScrollUpToSearchMatch(s) {
-							v.Info = "Pattern not found."
-						}
-					} else {
-						if !v.ScrollDownToSearchMatch(s) {
-							v.Info = "Pattern not found."
-						}
-					}
+				history, historyIndex := prompt.History()
+				v.FinishSearch(s, history[historyIndex])
+				if prompt != &vs.docSearch {
+					break
+				}
+				if err := saveSearchTerm(history[historyIndex]); err != nil {
+					v.Info = fmt.Sprint("Could not save search history: ", err)
 				}
-				v.Mode = gmir.Regular
 			case readline.Aborted:
-				v.Searchterm = ""
-				v.Searchpattern = nil
-				v.Cursor = 0
-				v.Mode = gmir.Regular
+				v.CancelSearch()
 			}
 		}
 	}
@@ -157,107 +340,171 @@ func processEvent(event tcell.Event, vs *views, s tcell.Screen) {
 func processKeyEvent(ev *tcell.EventKey, vs *views, s tcell.Screen) {
 	v := vs.activeView()
 	v.Info = ""
+	key := keyName(ev)
+	if key == "" {
+		return
+	}
+	if vs.pendingArg != nil {
+		f := vs.pendingArg
+		vs.pendingArg = nil
+		if ev.Key() == tcell.KeyRune {
+			f(vs, s, ev.Rune())
+		}
+		return
+	}
+	keys := vs.pendingKeys + key
+	if isBindingPrefix(keys) {
+		vs.pendingKeys = keys
+		return
+	}
+	if a, ok := boundAction(keys); ok {
+		vs.pendingKeys = ""
+		a.run(vs, s)
+		return
+	}
+	if vs.pendingKeys != "" {
+		// The pending keys are not continued, so they are run on their own
+		// and ev is processed as if no keys were pending.
+		pending := vs.pendingKeys
+		vs.pendingKeys = ""
+		if a, ok := boundAction(pending); ok {
+			a.run(vs, s)
+		}
+		processKeyEvent(ev, vs, s)
+		return
+	}
+	if ev.Key() == tcell.KeyRune && ev.Modifiers()&(tcell.ModCtrl|tcell.ModAlt) == 0 &&
+		gmir.DirectSelectors() && v.AddToSelector(ev.Rune()) {
+		v.Mode = gmir.Select
+		checkSelector(vs, s)
+	}
+}
+
+// processSelectorKey handles ev while a selector is being typed.
+func processSelectorKey(ev *tcell.EventKey, vs *views, s tcell.Screen) {
+	v := vs.activeView()
 	switch ev.Key() {
-	case tcell.KeyUp:
-		v.Scroll(s, 1)
-	case tcell.KeyDown:
-		v.Scroll(s, -1)
-	case tcell.KeyRight:
-		v.ColOffset += 1
-	case tcell.KeyPgUp:
-		_, height := s.Size()
-		v.Scroll(s, height-1)
-	case tcell.KeyPgDn:
-		_, height := s.Size()
-		v.Scroll(s, -height+1)
 	case tcell.KeyEsc:
-		v.ColOffset = 0
 		v.ClearSelector()
-		vs.showTOC = false
-	case tcell.KeyRune:
-		switch ev.Rune() {
-		case 'q':
-			s.Fini()
-			os.Exit(0)
-		case 'k':
-			v.Scroll(s, 1)
-		case 'j':
-			v.Scroll(s, -1)
-		case 'l':
-			v.ColOffset += 1
-		case 'u':
-			_, height := s.Size()
-			v.Scroll(s, height/2)
-		case 'd':
-			_, height := s.Size()
-			v.Scroll(s, -height/2)
-		case 'b':
-			_, height := s.Size()
-			v.Scroll(s, height-1)
-		case 'f', ' ':
-			_, height := s.Size()
-			v.Scroll(s, -height+1)
-		case 'g':
-			v.ScrollToTop(s)
-		case 'G':
-			v.ScrollToBottom(s)
-		case 'h':
-			v.ScrollToNextHeading(s)
-		case 'H':
-			v.ScrollToPrevHeading(s)
-		case 's':
-			v.ScrollToNextParagraph(s)
-		case 'S':
-			v.ScrollToPrevParagraph(s)
-		case 't':
-			if vs.toc.IsEmpty() {
-				v.Info = "Table of contents is empty"
-			} else {
-				vs.showTOC = true
-			}
-		case 'p':
-			if !v.ScrollUpToNextSearchMatch(s) {
-				v.Info = "No previous match found."
-			}
-		case 'n':
-			if !v.ScrollDownToNextSearchMatch(s) {
-				v.Info = "No further match found."
-			}
-		case '/':
-			v.Mode = gmir.Search
-			v.ClearSelector()
-		case '?':
-			v.Mode = gmir.ReverseSearch
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if v.SelectorIsEmpty() {
 			v.ClearSelector()
-		case '1', '2', '3', '4', '5', '6', '7', '8', '9', '0':
-			digit, _ := strconv.Atoi(string(ev.Rune()))
-			v.AddDigitToSelector(digit)
-			if v.SelectorIsValid() {
-				if vs.showTOC {
-					vs.showTOC = false
-					vs.doc.ScrollToNthHeading(s, v.SelectorIndex())
-					v.ClearSelector()
-				} else {
-					s.Fini()
-					fmt.Println(v.LinkURL())
-					os.Exit(0)
-				}
-			}
-		case 'v':
-			v.HideURLs()
-			v.FixLineOffset(s)
-		case 'V':
-			v.ShowURLs()
-			v.FixLineOffset(s)
+		} else {
+			v.RemoveFromSelector()
+		}
+	case tcell.KeyRune:
+		if v.AddToSelector(ev.Rune()) {
+			checkSelector(vs, s)
 		}
 	}
 }
 
+// checkSelector selects the entry of the selector of the active view,
+// once it is complete.
+func checkSelector(vs *views, s tcell.Screen) {
+	v := vs.activeView()
+	if !v.SelectorIsComplete() {
+		return
+	}
+	valid, index := v.SelectorIsValid(), v.SelectorIndex()
+	v.ClearSelector()
+	if valid {
+		selectEntry(vs, s, index)
+	} else {
+		v.Info = "Invalid selector"
+	}
+}
+
+// processFilterKey handles ev while the filter of the link picker is
+// being typed.
+func processFilterKey(ev *tcell.EventKey, vs *views, s tcell.Screen) {
+	if ev.Key() == tcell.KeyEnter {
+		vs.filter.Clear()
+		if index, ok := vs.picker.Focus(); ok {
+			selectEntry(vs, s, index)
+		} else {
+			vs.picker.Info = "No matching link"
+		}
+		return
+	}
+	switch vs.filter.ProcessKey(ev) {
+	case readline.Reading:
+		pickLinks(vs, s)
+	case readline.Aborted:
+		vs.showPicker = false
+	}
+}
+
+// processCommandKey handles ev while a command is being typed.
+func processCommandKey(ev *tcell.EventKey, vs *views, s tcell.Screen) {
+	if ev.Key() == tcell.KeyTab {
+		completeCommand(&vs.command)
+		return
+	}
+	status := vs.command.ProcessKey(ev)
+	if status == readline.Reading {
+		return
+	}
+	v := vs.activeView()
+	v.Mode = gmir.Regular
+	v.Prompt = nil
+	if status == readline.Done {
+		history, historyIndex := vs.command.History()
+		runCommand(vs, s, history[historyIndex])
+	}
+}
+
+// pickLinks shows the link picker with the filter, that is being typed.
+func pickLinks(vs *views, s tcell.Screen) {
+	vs.picker = vs.doc.LinkPickerView(vs.filter.Input())
+	vs.picker.Mode = gmir.Filter
+	vs.picker.Prompt = &vs.filter
+	vs.showPicker = true
+}
+
+// loadSearchHistory returns the saved search terms, from oldest to
+// newest.
+func loadSearchHistory() ([]string, error) {
+	if searchHistory.Path == "" {
+		return nil, nil
+	}
+	return searchHistory.Lines()
+}
+
+// saveSearchTerm appends term to the search history file.
+func saveSearchTerm(term string) error {
+	if searchHistory.Path == "" || strings.ContainsAny(term, "\r\n") {
+		return nil
+	}
+	return searchHistory.Append(term)
+}
+
+// selectEntry jumps to the heading with the given index, if the table
+// of contents is shown, or follows the link with the given index
+// otherwise. While the link picker is shown, index refers to its links.
+func selectEntry(vs *views, s tcell.Screen, index int) {
+	if vs.showPicker {
+		vs.showPicker = false
+		followLink(vs, s, vs.picker.NthLink(index), vs.picker.OriginalIndex(index))
+	} else if vs.showTOC {
+		vs.showTOC = false
+		vs.doc.ScrollToNthHeading(s, index)
+	} else {
+		followLink(vs, s, vs.doc.NthLink(index), index)
+	}
+}
+
+// inputPath returns the path of the given FILE or an empty string, if
+// standard input is read.
+func inputPath() string {
+	if len(flag.Args()) == 1 {
+		return flag.Args()[0]
+	}
+	return ""
+}
+
 func getInput() io.ReadCloser {
-	if len(flag.Args()) > 1 {
-		fmt.Fprintln(os.Stderr, "Too many arguments.")
-		os.Exit(1)
-	} else if len(flag.Args()) == 1 {
+	if len(flag.Args()) == 1 {
 		file, err := os.Open(flag.Args()[0])
 		if err != nil {
 			fmt.Fprintln(os.Stderr, "Could not open given file:", err)
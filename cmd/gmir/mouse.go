@@ -0,0 +1,35 @@
+package main
+
+import (
+	"github.com/codesoap/gmir"
+	"github.com/gdamore/tcell/v2"
+)
+
+// wheelLines is the amount of lines scrolled per step of the mouse
+// wheel.
+const wheelLines = 3
+
+func processMouseEvent(ev *tcell.EventMouse, vs *views, s tcell.Screen) {
+	v := vs.activeView()
+	buttons := ev.Buttons()
+	pressed := buttons &^ vs.mouseButtons
+	vs.mouseButtons = buttons & (tcell.Button1 | tcell.Button2 | tcell.Button3)
+	switch {
+	case buttons&tcell.WheelUp != 0:
+		v.Scroll(s, wheelLines)
+	case buttons&tcell.WheelDown != 0:
+		v.Scroll(s, -wheelLines)
+	case pressed&tcell.Button1 != 0 && v.Mode == gmir.Regular:
+		x, y := ev.Position()
+		line, _, ok := v.LineAt(s, x, y)
+		if !ok {
+			return
+		}
+		if index, ok := v.SelectableIndex(line); ok {
+			v.Info = ""
+			v.ClearSelector()
+			vs.pendingKeys = ""
+			selectEntry(vs, s, index)
+		}
+	}
+}
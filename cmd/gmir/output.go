@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// A result describes, how gmir was left. It is printed as a JSON object,
+// if the output format is json. Unlike the selectors on the screen,
+// which start at 1, the index of the first link is 0.
+type result struct {
+	Action     string `json:"action"` // One of follow, quit, back and reload.
+	URL        string `json:"url,omitempty"`
+	Resolved   string `json:"resolved,omitempty"` // URL resolved against the base URL.
+	Label      string `json:"label,omitempty"`
+	Index      *int   `json:"index,omitempty"` // The 0-based index of the link in the document.
+	Line       int    `json:"line"`            // The scroll position of the document.
+	LineOffset int    `json:"lineOffset"`
+}
+
+// newResult returns a result for action with the scroll position of the
+// current document.
+func newResult(vs *views, action string) result {
+	line, lineOffset := vs.doc.Position()
+	return result{Action: action, Line: line, LineOffset: lineOffset}
+}
+
+// exit quits gmir and prints r in the output format. The reading
+// position of the current document is saved.
+func exit(vs *views, s tcell.Screen, r result) {
+	s.Fini()
+	if err := readingPositions.save(vs.key, vs.doc); err != nil {
+		fmt.Fprintln(os.Stderr, "Could not save reading position:", err)
+	}
+	if err := writeResult(os.Stdout, r, oFlag); err != nil {
+		fmt.Fprintln(os.Stderr, "Could not write output:", err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// writeResult writes r to w in the given output format.
+func writeResult(w io.Writer, r result, format string) error {
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		return encoder.Encode(r)
+	} else if r.Action == "follow" {
+		_, err := fmt.Fprintln(w, r.Resolved)
+		return err
+	}
+	return nil
+}
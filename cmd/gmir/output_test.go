@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/codesoap/gmir"
+	"github.com/gdamore/tcell/v2"
+)
+
+var outputDoc = "=> gemini://a.example/ A\n" +
+	"=> /b B\n" +
+	"=> c.gmi C\n" +
+	"=> ../about.gmi About\n" +
+	strings.Repeat("text\n", 30)
+
+var outputTestCases = []struct {
+	link     int // The index of the followed link; -1 for quitting.
+	format   string
+	expected string
+}{
+	{3, "json", `{"action":"follow","url":"../about.gmi","resolved":"gemini://example.org/about.gmi","label":"About","index":3,"line":12,"lineOffset":0}` + "\n"},
+	{0, "json", `{"action":"follow","url":"gemini://a.example/","resolved":"gemini://a.example/","label":"A","index":0,"line":12,"lineOffset":0}` + "\n"},
+	{1, "json", `{"action":"follow","url":"/b","resolved":"gemini://example.org/b","label":"B","index":1,"line":12,"lineOffset":0}` + "\n"},
+	{-1, "json", `{"action":"quit","line":12,"lineOffset":0}` + "\n"},
+	{3, "text", "gemini://example.org/about.gmi\n"},
+	{-1, "text", ""},
+}
+
+func TestOutput(t *testing.T) {
+	s := tcell.NewSimulationScreen("")
+	if err := s.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Fini()
+	s.SetSize(80, 10)
+	doc, err := gmir.NewView(strings.NewReader(outputDoc), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := "gemini://example.org/dir/index.gmi"
+	if err := doc.SetBase(base); err != nil {
+		t.Fatal(err)
+	}
+	doc.ScrollToPosition(s, 12, 0)
+	vs := views{doc: doc, base: base}
+	for _, testCase := range outputTestCases {
+		var r result
+		if testCase.link < 0 {
+			r = newResult(&vs, "quit")
+		} else {
+			r = followResult(&vs, vs.doc.NthLink(testCase.link), testCase.link)
+		}
+		var out bytes.Buffer
+		if err := writeResult(&out, r, testCase.format); err != nil {
+			t.Fatal(err)
+		}
+		if out.String() != testCase.expected {
+			t.Errorf("Got '%s', expected '%s'.", out.String(), testCase.expected)
+		}
+	}
+}
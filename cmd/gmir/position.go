@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"path/filepath"
+
+	"github.com/codesoap/gmir"
+	"github.com/codesoap/gmir/statefile"
+	"github.com/gdamore/tcell/v2"
+)
+
+// A positionEntry is a line of the positions file.
+type positionEntry struct {
+	Key    string      `json:"key"`
+	Anchor gmir.Anchor `json:"anchor"`
+}
+
+// positions contains the last reading position of each document by its
+// key. The key of a document is the absolute path of its file or the
+// hash of its content, if it was read from standard input.
+type positions struct {
+	file    statefile.File // Has an empty path, if positions are not persisted.
+	anchors map[string]gmir.Anchor
+}
+
+func loadPositions(path string) (*positions, error) {
+	key := func(line string) string {
+		var entry positionEntry
+		json.Unmarshal([]byte(line), &entry)
+		return entry.Key
+	}
+	p := &positions{file: statefile.New(path, key), anchors: make(map[string]gmir.Anchor)}
+	if path == "" {
+		return p, nil
+	}
+	lines, err := p.file.Lines()
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range lines {
+		var entry positionEntry
+		if err := json.Unmarshal([]byte(line), &entry); err == nil {
+			p.anchors[entry.Key] = entry.Anchor
+		}
+	}
+	return p, nil
+}
+
+// save remembers the position of doc under key. Nothing is saved for an
+// empty key.
+func (p *positions) save(key string, doc gmir.View) error {
+	if key == "" {
+		return nil
+	}
+	a := doc.Anchor()
+	if old, ok := p.anchors[key]; ok && old == a {
+		return nil
+	}
+	p.anchors[key] = a
+	if p.file.Path == "" {
+		return nil
+	}
+	line, err := json.Marshal(positionEntry{Key: key, Anchor: a})
+	if err != nil {
+		return err
+	}
+	return p.file.Append(string(line))
+}
+
+// restore scrolls doc to the position saved under key, if there is one.
+func (p *positions) restore(key string, s tcell.Screen, doc *gmir.View) {
+	if a, ok := p.anchors[key]; ok && key != "" {
+		doc.ScrollToAnchor(s, a)
+	}
+}
+
+// fileKey returns the key of the document read from path.
+func fileKey(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return "file:" + path
+}
+
+// contentKey returns the key of a document read from standard input,
+// whose content has been written to h.
+func contentKey(h hash.Hash) string {
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/codesoap/gmir"
+	"github.com/gdamore/tcell/v2"
+)
+
+// numberedLines returns the text lines "line from" to "line to".
+func numberedLines(from, to int) string {
+	var b strings.Builder
+	for i := from; i <= to; i++ {
+		fmt.Fprintf(&b, "line %d\n", i)
+	}
+	return b.String()
+}
+
+func newTestScreen(t *testing.T) tcell.Screen {
+	s := tcell.NewSimulationScreen("")
+	if err := s.Init(); err != nil {
+		t.Fatal(err)
+	}
+	s.SetSize(80, 10)
+	t.Cleanup(s.Fini)
+	return s
+}
+
+// firstLineText returns the text of the first displayed line of v.
+func firstLineText(t *testing.T, v gmir.View) string {
+	t.Helper()
+	return v.Anchor().Text
+}
+
+func TestFileKey(t *testing.T) {
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if fileKey("doc.gmi") != fileKey(filepath.Join(dir, "doc.gmi")) {
+		t.Errorf("Relative and absolute paths have different keys.")
+	}
+	if fileKey("doc.gmi") == fileKey("other.gmi") {
+		t.Errorf("Different files have the same key.")
+	}
+}
+
+func TestContentKey(t *testing.T) {
+	a, b, c := sha256.New(), sha256.New(), sha256.New()
+	a.Write([]byte("# Doc\n"))
+	b.Write([]byte("# Doc\n"))
+	c.Write([]byte("# Other\n"))
+	if contentKey(a) != contentKey(b) {
+		t.Errorf("Equal content has different keys.")
+	}
+	if contentKey(a) == contentKey(c) {
+		t.Errorf("Different content has the same key.")
+	}
+}
+
+func TestPositionsArePersisted(t *testing.T) {
+	s := newTestScreen(t)
+	path := filepath.Join(t.TempDir(), "positions")
+	p, err := loadPositions(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := gmir.NewView(strings.NewReader(numberedLines(1, 100)), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc.ScrollToPosition(s, 40, 0)
+	if err = p.save("file:/doc.gmi", doc); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := loadPositions(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Lines have been added above the position in the meantime.
+	changed, err := gmir.NewView(strings.NewReader(numberedLines(-4, 100)), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	reloaded.restore("file:/other.gmi", s, &changed)
+	if text := firstLineText(t, changed); text != "line -4" {
+		t.Errorf("Unknown key scrolled to '%s'.", text)
+	}
+	reloaded.restore("file:/doc.gmi", s, &changed)
+	if text := firstLineText(t, changed); text != "line 41" {
+		t.Errorf("Restored position is at '%s', expected 'line 41'.", text)
+	}
+}
+
+// withDocument sets up readingPositions and a views, that shows the
+// document at path, which contains content.
+func withDocument(t *testing.T, s tcell.Screen, content string) (*views, string) {
+	t.Helper()
+	var err error
+	if readingPositions, err = loadPositions(""); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "doc.gmi")
+	if err = os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := readView(path, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vs := &views{doc: doc, path: path, key: fileKey(path)}
+	vs.toc = vs.doc.TOCView()
+	return vs, path
+}
+
+func TestPositionIsRestoredOnOpen(t *testing.T) {
+	s := newTestScreen(t)
+	vs, path := withDocument(t, s, numberedLines(1, 100))
+	vs.doc.ScrollToPosition(s, 60, 0)
+	if err := readingPositions.save(vs.key, vs.doc); err != nil {
+		t.Fatal(err)
+	}
+	if err := openFile(vs, s, path, ""); err != nil {
+		t.Fatal(err)
+	}
+	if text := firstLineText(t, vs.doc); text != "line 61" {
+		t.Errorf("Opened document is at '%s', expected 'line 61'.", text)
+	}
+}
+
+func TestPositionIsKeptOnReload(t *testing.T) {
+	s := newTestScreen(t)
+	vs, path := withDocument(t, s, numberedLines(1, 100))
+	vs.doc.ScrollToPosition(s, 20, 0)
+	if err := os.WriteFile(path, []byte(numberedLines(-9, 100)), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := vs.reload(s); err != nil {
+		t.Fatal(err)
+	}
+	if text := firstLineText(t, vs.doc); text != "line 21" {
+		t.Errorf("Reloaded document is at '%s', expected 'line 21'.", text)
+	}
+}
@@ -1,6 +1,9 @@
 package gmir
 
 import (
+	"fmt"
+	"sort"
+
 	"github.com/gdamore/tcell/v2"
 )
 
@@ -14,5 +17,107 @@ var (
 	styleHeading3     = tcell.StyleDefault.Bold(true)
 	styleList         = tcell.StyleDefault
 	styleQuote        = tcell.StyleDefault.Italic(true)
+	styleAltText      = tcell.StyleDefault.Dim(true).Italic(true)
 	styleBar          = tcell.StyleDefault.Reverse(true)
+
+	// The style of search matches. Its colors and attributes are added
+	// to the style of the matching text.
+	styleSearch = tcell.StyleDefault.Reverse(true)
+
+	// The style of the focused link. Its colors and attributes are added
+	// to the style of the link.
+	styleFocus = tcell.StyleDefault.Bold(true).Underline(true)
+
+	// Styles for links by the scheme of their URL. Their colors and
+	// attributes are added to styleLink.
+	styleLinkGemini   = tcell.StyleDefault
+	styleLinkHTTP     = tcell.StyleDefault.Foreground(tcell.ColorPurple)
+	styleLinkGopher   = tcell.StyleDefault.Foreground(tcell.ColorOlive)
+	styleLinkMailto   = tcell.StyleDefault
+	styleLinkFile     = tcell.StyleDefault
+	styleLinkRelative = tcell.StyleDefault
+	styleLinkUnknown  = tcell.StyleDefault.Dim(true)
+
+	// The style of links, that have been visited before. Its colors and
+	// attributes are added to the style of the link.
+	styleVisited = tcell.StyleDefault.Dim(true)
+
+	// Styles for tokens within syntax highlighted preformatted blocks:
+	styleKeyword = tcell.StyleDefault.Bold(true)
+	styleString  = tcell.StyleDefault.Foreground(tcell.ColorGreen)
+	styleComment = tcell.StyleDefault.Foreground(tcell.ColorGray)
+	styleNumber  = tcell.StyleDefault.Foreground(tcell.ColorTeal)
 )
+
+// styles contains all configurable styles by name.
+var styles = map[string]*tcell.Style{
+	"text":          &styleText,
+	"link":          &styleLink,
+	"link-gemini":   &styleLinkGemini,
+	"link-http":     &styleLinkHTTP,
+	"link-gopher":   &styleLinkGopher,
+	"link-mailto":   &styleLinkMailto,
+	"link-file":     &styleLinkFile,
+	"link-relative": &styleLinkRelative,
+	"link-unknown":  &styleLinkUnknown,
+	"visited":       &styleVisited,
+	"preformatted":  &stylePrefromatted,
+	"heading1":      &styleHeading1,
+	"heading2":      &styleHeading2,
+	"heading3":      &styleHeading3,
+	"list":          &styleList,
+	"quote":         &styleQuote,
+	"alttext":       &styleAltText,
+	"bar":           &styleBar,
+	"search":        &styleSearch,
+	"focus":         &styleFocus,
+	"keyword":       &styleKeyword,
+	"string":        &styleString,
+	"comment":       &styleComment,
+	"number":        &styleNumber,
+}
+
+// StyleNames returns the sorted names of all styles, that can be set
+// with SetStyle.
+func StyleNames() []string {
+	names := make([]string, 0, len(styles))
+	for name := range styles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetStyle replaces the style with the given name.
+func SetStyle(name string, style tcell.Style) error {
+	s, ok := styles[name]
+	if !ok {
+		return fmt.Errorf("unknown style '%s'", name)
+	}
+	*s = style
+	return nil
+}
+
+// SetMaxTextWidth sets the width, to which text is wrapped, if the
+// screen is wide enough.
+func SetMaxTextWidth(width int) error {
+	if width < 1 {
+		return fmt.Errorf("text width must be positive")
+	}
+	maxTextWidth = width
+	return nil
+}
+
+// addStyle returns base with the colors and attributes of addition
+// applied.
+func addStyle(base, addition tcell.Style) tcell.Style {
+	fg, bg, attrs := addition.Decompose()
+	_, _, baseAttrs := base.Decompose()
+	if fg != tcell.ColorDefault {
+		base = base.Foreground(fg)
+	}
+	if bg != tcell.ColorDefault {
+		base = base.Background(bg)
+	}
+	return base.Attributes(baseAttrs | attrs)
+}
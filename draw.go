@@ -3,8 +3,8 @@ package gmir
 import (
 	"strings"
 
+	"github.com/codesoap/gmir/highlight"
 	"github.com/codesoap/gmir/parser"
-	"github.com/codesoap/gmir/selector"
 	"github.com/gdamore/tcell/v2"
 	"github.com/mattn/go-runewidth"
 )
@@ -27,18 +27,12 @@ will always be wide enough to fit the largest selector in the document.
 */
 func (v View) Draw(screen tcell.Screen) {
 	screenWidth, screenHeight := screen.Size()
-	leftSpace, selectorColWidth, textWidth := v.columnWidths(screenWidth)
+	_, selectorColWidth, textWidth := v.columnWidths(screenWidth)
 	if screenWidth < selectorColWidth+8 || screenHeight < 2 {
 		// Screen too small.
 		return
 	}
-	maxColOffset := v.maxLineWidth(textWidth) - textWidth
-	if v.ColOffset > maxColOffset {
-		leftSpace -= maxColOffset
-	} else {
-		leftSpace -= v.ColOffset
-	}
-	v.drawSelectorAndGMIColumn(screen, leftSpace, selectorColWidth, textWidth)
+	v.drawSelectorAndGMIColumn(screen, v.leftEdge(screenWidth), selectorColWidth, textWidth)
 	v.drawBar(screen)
 	screen.Show()
 }
@@ -57,6 +51,7 @@ func (v View) maxLineWidth(wrappedTextWidth int) int {
 
 func (v View) drawSelectorAndGMIColumn(screen tcell.Screen, offset, selectorColWidth, textWidth int) {
 	_, screenHeight := screen.Size()
+	count := v.selectableCount()
 	drawnLines, selectorIndex := 0, -1
 	for i := 0; i < len(v.lines) && drawnLines < screenHeight-1; i++ {
 		isSelectable := v.isSelectable(v.lines[i])
@@ -67,15 +62,44 @@ func (v View) drawSelectorAndGMIColumn(screen tcell.Screen, offset, selectorColW
 			continue
 		}
 		if isSelectable {
-			selector := selector.FromIndex(selectorIndex)
-			selector = strings.Repeat(" ", selectorColWidth-len(selector)-1) + selector
-			emitStr(screen, offset, drawnLines, styleText, selector)
+			drawSelector(screen, offset, drawnLines, selectorColWidth, selectors.FromIndex(selectorIndex, count))
 		}
 		drawnLines = v.drawLine(screen, i, drawnLines, offset+selectorColWidth, textWidth)
+		if link, isLink := v.lines[i].(parser.LinkLine); isLink && drawnLines < screenHeight {
+			drawMarker(screen, link, offset+selectorColWidth, drawnLines-1, textWidth)
+		}
 	}
 }
 
-func emitStr(s tcell.Screen, x, y int, style tcell.Style, str string) {
+// drawMarker draws the marker of the scheme of link behind its last
+// wrapped line, which is drawn at row y. The marker may intrude into the
+// right space.
+func drawMarker(c canvas, link parser.LinkLine, x, y, textWidth int) {
+	marker := link.Scheme().Marker()
+	if marker == "" {
+		return
+	}
+	wrappedLines := linesOfWrappable(link, textWidth)
+	if len(wrappedLines) > 1 {
+		x += link.IndentWidth()
+	}
+	x += runewidth.StringWidth(strings.TrimRight(wrappedLines[len(wrappedLines)-1], " ")) + 1
+	emitStr(c, x, y, styleFor(link), marker)
+}
+
+// A canvas is the part of tcell.Screen, that is needed to draw text.
+type canvas interface {
+	SetContent(x, y int, primary rune, combining []rune, style tcell.Style)
+}
+
+// drawSelector draws the given selector right aligned into the selector
+// column, leaving one blank at the end.
+func drawSelector(c canvas, x, y, selectorColWidth int, selector string) {
+	selector = strings.Repeat(" ", selectorColWidth-runewidth.StringWidth(selector)-1) + selector
+	emitStr(c, x, y, styleText, selector)
+}
+
+func emitStr(s canvas, x, y int, style tcell.Style, str string) {
 	for _, c := range str {
 		var comb []rune
 		w := runewidth.RuneWidth(c)
@@ -91,9 +115,12 @@ func emitStr(s tcell.Screen, x, y int, style tcell.Style, str string) {
 
 // drawLine draws the given line, wrapping it if necessary and returns
 // the amount of lines written to screen.
-func (v View) drawLine(screen tcell.Screen, lineIndex, drawnLines, offset, textWidth int) int {
+func (v View) drawLine(screen canvas, lineIndex, drawnLines, offset, textWidth int) int {
 	line := v.lines[lineIndex]
 	style := styleFor(line)
+	if lineIndex == v.focus {
+		style = addStyle(style, styleFocus)
+	}
 	var highlights [][]int
 	if v.Searchpattern != nil {
 		highlights = v.Searchpattern.FindAllStringIndex(line.Text(), -1)
@@ -111,6 +138,9 @@ func (v View) drawLine(screen tcell.Screen, lineIndex, drawnLines, offset, textW
 			}
 			highlights = subFromHighlights(highlights, len(wrappedLine))
 		}
+	} else if spans, isHighlighted := v.syntax[lineIndex]; isHighlighted {
+		emitStrWithSyntax(screen, offset, drawnLines, line.Text(), spans, highlights)
+		drawnLines++
 	} else {
 		emitStrWithHighlights(screen, offset, drawnLines, stylePrefromatted, line.Text(), highlights)
 		drawnLines++
@@ -119,11 +149,15 @@ func (v View) drawLine(screen tcell.Screen, lineIndex, drawnLines, offset, textW
 }
 
 func styleFor(line parser.Line) tcell.Style {
-	switch line.(type) {
+	switch l := line.(type) {
 	case parser.TextLine:
 		return styleText
 	case parser.LinkLine:
-		return styleLink
+		style := addStyle(styleLink, styleForScheme(l.Scheme()))
+		if isVisited(l.ResolvedURL()) {
+			style = addStyle(style, styleVisited)
+		}
+		return style
 	case parser.PreformattedLine:
 		return stylePrefromatted
 	case parser.Heading1Line:
@@ -136,10 +170,30 @@ func styleFor(line parser.Line) tcell.Style {
 		return styleList
 	case parser.QuoteLine:
 		return styleQuote
+	case parser.AltTextLine:
+		return styleAltText
 	}
 	panic("unknown line type")
 }
 
+func styleForScheme(scheme parser.Scheme) tcell.Style {
+	switch scheme {
+	case parser.SchemeGemini:
+		return styleLinkGemini
+	case parser.SchemeHTTP:
+		return styleLinkHTTP
+	case parser.SchemeGopher:
+		return styleLinkGopher
+	case parser.SchemeMailto:
+		return styleLinkMailto
+	case parser.SchemeFile:
+		return styleLinkFile
+	case parser.SchemeRelative:
+		return styleLinkRelative
+	}
+	return styleLinkUnknown
+}
+
 func linesOfWrappable(wrappable parser.WrappableLine, width int) []string {
 	wrapIndexes := wrappable.WrapIndexes(width)
 	lines := make([]string, len(wrapIndexes)+1)
@@ -164,7 +218,25 @@ func subFromHighlights(highlights [][]int, x int) [][]int {
 	return newHighlights
 }
 
-func emitStrWithHighlights(s tcell.Screen, x, y int, style tcell.Style, str string, highlights [][]int) {
+func emitStrWithHighlights(s canvas, x, y int, style tcell.Style, str string, highlights [][]int) {
+	for i, c := range str {
+		var comb []rune
+		w := runewidth.RuneWidth(c)
+		if w == 0 {
+			comb = []rune{c}
+			c = ' '
+			w = 1
+		}
+		if withinHighlight(i, highlights) {
+			s.SetContent(x, y, c, comb, addStyle(style, styleSearch))
+		} else {
+			s.SetContent(x, y, c, comb, style)
+		}
+		x += w
+	}
+}
+
+func emitStrWithSyntax(s canvas, x, y int, str string, spans []highlight.Span, highlights [][]int) {
 	for i, c := range str {
 		var comb []rune
 		w := runewidth.RuneWidth(c)
@@ -173,7 +245,11 @@ func emitStrWithHighlights(s tcell.Screen, x, y int, style tcell.Style, str stri
 			c = ' '
 			w = 1
 		}
-		s.SetContent(x, y, c, comb, style.Reverse(withinHighlight(i, highlights)))
+		style := styleForKind(highlight.KindAt(spans, i))
+		if withinHighlight(i, highlights) {
+			style = addStyle(style, styleSearch)
+		}
+		s.SetContent(x, y, c, comb, style)
 		x += w
 	}
 }
@@ -0,0 +1,46 @@
+package gmir
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/codesoap/gmir/parser"
+	"github.com/gdamore/tcell/v2"
+)
+
+var markerTestCases = []struct {
+	input    string
+	row      int
+	expected string // The text of the row without the selector column.
+}{
+	{"=> https://example.org/ Web\n", 0, "=> Web [www]"},
+	{"=> gemini://example.org/ Gemini\n", 0, "=> Gemini"},
+	{"=> mailto:user@example.org " + strings.Repeat("long ", 8) + "name\n", 1, "   long name [mail]"},
+}
+
+func TestDrawMarker(t *testing.T) {
+	defer func(show bool) { parser.ShowURLs = show }(parser.ShowURLs)
+	parser.ShowURLs = false
+	s := tcell.NewSimulationScreen("")
+	if err := s.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Fini()
+	s.SetSize(40, 4)
+	for _, testCase := range markerTestCases {
+		v, err := NewView(strings.NewReader(testCase.input), "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.Clear()
+		v.Draw(s)
+		cells, width, _ := s.GetContents()
+		var row strings.Builder
+		for _, c := range cells[testCase.row*width+2 : (testCase.row+1)*width] {
+			row.WriteString(string(c.Runes))
+		}
+		if got := strings.TrimRight(row.String(), " "); got != testCase.expected {
+			t.Errorf("Got '%s', expected '%s'.", got, testCase.expected)
+		}
+	}
+}
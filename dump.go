@@ -0,0 +1,166 @@
+package gmir
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
+)
+
+// Dump writes the whole document of v to w, laid out like Draw would
+// do for a screen of the given width, but without left space and
+// without search highlights. If ansi is true, styles are applied with
+// ANSI SGR escape sequences; otherwise plain text is written.
+func (v View) Dump(w io.Writer, width int, ansi bool) error {
+	_, selectorColWidth, textWidth := v.columnWidths(width)
+	if textWidth < 1 {
+		return fmt.Errorf("width %d is too small", width)
+	}
+	v.line, v.lineOffset = 0, 0
+	v.Searchpattern = nil
+	buffer := cellBuffer{}
+	count := v.selectableCount()
+	drawnLines, selectorIndex := 0, -1
+	for i, line := range v.lines {
+		if v.isSelectable(line) {
+			selectorIndex++
+			drawSelector(&buffer, 0, drawnLines, selectorColWidth, selectors.FromIndex(selectorIndex, count))
+		}
+		drawnLines = v.drawLine(&buffer, i, drawnLines, selectorColWidth, textWidth)
+	}
+	return buffer.write(w, drawnLines, ansi)
+}
+
+// WriteSource writes the GMI, that v was read from, to w. Derived views,
+// like the table of contents, have no source.
+func (v View) WriteSource(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, line := range v.source.RawLines() {
+		if _, err := bw.WriteString(line + "\n"); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+type cell struct {
+	primary   rune
+	combining []rune
+	style     tcell.Style
+	width     int // Zero for cells, that have not been set.
+}
+
+// cellBuffer is a canvas, that stores rows of cells in memory.
+type cellBuffer struct {
+	rows [][]cell
+}
+
+func (b *cellBuffer) SetContent(x, y int, primary rune, combining []rune, style tcell.Style) {
+	for len(b.rows) <= y {
+		b.rows = append(b.rows, nil)
+	}
+	for len(b.rows[y]) <= x {
+		b.rows[y] = append(b.rows[y], cell{})
+	}
+	w := runewidth.RuneWidth(primary)
+	if w < 1 {
+		w = 1
+	}
+	b.rows[y][x] = cell{primary, combining, style, w}
+}
+
+// write writes the given amount of rows to w. Trailing blanks are
+// omitted. If ansi is true, styled blanks are kept, because their
+// style may be visible.
+func (b cellBuffer) write(w io.Writer, rowCount int, ansi bool) error {
+	out := bufio.NewWriter(w)
+	for y := 0; y < rowCount; y++ {
+		var row []cell
+		if y < len(b.rows) {
+			row = trimRow(b.rows[y], ansi)
+		}
+		style := tcell.StyleDefault
+		for x := 0; x < len(row); x++ {
+			c := row[x]
+			if c.width == 0 {
+				c = cell{primary: ' ', style: tcell.StyleDefault, width: 1}
+			}
+			if ansi && c.style != style {
+				out.WriteString(sgr(c.style))
+				style = c.style
+			}
+			out.WriteRune(c.primary)
+			for _, r := range c.combining {
+				out.WriteRune(r)
+			}
+			x += c.width - 1
+		}
+		if style != tcell.StyleDefault {
+			out.WriteString(sgr(tcell.StyleDefault))
+		}
+		out.WriteByte('\n')
+	}
+	return out.Flush()
+}
+
+// trimRow returns row without trailing blanks. If keepStyled is true,
+// blanks with a style other than the default are not removed.
+func trimRow(row []cell, keepStyled bool) []cell {
+	for len(row) > 0 {
+		last := row[len(row)-1]
+		if last.width != 0 && (last.primary != ' ' || keepStyled && last.style != tcell.StyleDefault) {
+			break
+		}
+		row = row[:len(row)-1]
+	}
+	return row
+}
+
+// sgr returns the ANSI escape sequence, that resets all attributes and
+// then applies style.
+func sgr(style tcell.Style) string {
+	fg, bg, attrs := style.Decompose()
+	params := []string{"0"}
+	for _, a := range []struct {
+		mask  tcell.AttrMask
+		param string
+	}{
+		{tcell.AttrBold, "1"},
+		{tcell.AttrDim, "2"},
+		{tcell.AttrItalic, "3"},
+		{tcell.AttrUnderline, "4"},
+		{tcell.AttrBlink, "5"},
+		{tcell.AttrReverse, "7"},
+		{tcell.AttrStrikeThrough, "9"},
+	} {
+		if attrs&a.mask != 0 {
+			params = append(params, a.param)
+		}
+	}
+	if fg != tcell.ColorDefault {
+		params = append(params, sgrColor(fg, 30))
+	}
+	if bg != tcell.ColorDefault {
+		params = append(params, sgrColor(bg, 40))
+	}
+	return "\x1b[" + strings.Join(params, ";") + "m"
+}
+
+// sgrColor returns the SGR parameter for the given color. base is 30
+// for foreground and 40 for background colors.
+func sgrColor(color tcell.Color, base int) string {
+	if color.IsRGB() {
+		r, g, b := color.RGB()
+		return fmt.Sprintf("%d;2;%d;%d;%d", base+8, r, g, b)
+	}
+	index := int(color - tcell.ColorValid)
+	if index < 8 {
+		return fmt.Sprint(base + index)
+	} else if index < 16 {
+		return fmt.Sprint(base + 60 + index - 8)
+	}
+	return fmt.Sprintf("%d;5;%d", base+8, index)
+}
@@ -0,0 +1,75 @@
+package gmir
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+const dumpInput = "# Title\nSome text, that is wrapped here.\n=> gemini://example.org/ Example\n"
+
+var dumpTestCases = []struct {
+	ansi     bool
+	expected string
+}{
+	{false, "  # Title\n" +
+		"  Some text, that is\n" +
+		"  wrapped here.\n" +
+		"1 => Example\n" +
+		"     (gemini://examp\n" +
+		"     le.org/)\n"},
+	{true, "  \x1b[0;1m# Title\x1b[0m\n" +
+		"  Some text, that is\n" +
+		"  wrapped here.\n" +
+		"1 \x1b[0;94m=> Example \x1b[0m\n" +
+		"     \x1b[0;94m(gemini://examp\x1b[0m\n" +
+		"     \x1b[0;94mle.org/)\x1b[0m\n"},
+}
+
+func TestDump(t *testing.T) {
+	v, err := NewView(strings.NewReader(dumpInput), "")
+	if err != nil {
+		t.Fatalf("Could not parse input: %v", err)
+	}
+	for _, testCase := range dumpTestCases {
+		var out bytes.Buffer
+		if err := v.Dump(&out, 20, testCase.ansi); err != nil {
+			t.Fatalf("Could not dump: %v", err)
+		}
+		if out.String() != testCase.expected {
+			t.Errorf("Got %q with ansi=%v, expected %q.", out.String(), testCase.ansi, testCase.expected)
+		}
+	}
+}
+
+func TestDumpTooNarrow(t *testing.T) {
+	v, err := NewView(strings.NewReader(dumpInput), "")
+	if err != nil {
+		t.Fatalf("Could not parse input: %v", err)
+	}
+	if err := v.Dump(&bytes.Buffer{}, 2, false); err == nil {
+		t.Errorf("Dumping to a width of 2 did not fail.")
+	}
+}
+
+var sgrTestCases = []struct {
+	style    tcell.Style
+	expected string
+}{
+	{tcell.StyleDefault, "\x1b[0m"},
+	{tcell.StyleDefault.Bold(true).Underline(true), "\x1b[0;1;4m"},
+	{tcell.StyleDefault.Foreground(tcell.ColorMaroon), "\x1b[0;31m"},
+	{tcell.StyleDefault.Foreground(tcell.ColorRed), "\x1b[0;91m"},
+	{tcell.StyleDefault.Background(tcell.PaletteColor(208)), "\x1b[0;48;5;208m"},
+	{tcell.StyleDefault.Foreground(tcell.NewRGBColor(255, 135, 0)), "\x1b[0;38;2;255;135;0m"},
+}
+
+func TestSGR(t *testing.T) {
+	for _, testCase := range sgrTestCases {
+		if got := sgr(testCase.style); got != testCase.expected {
+			t.Errorf("Got %q, expected %q.", got, testCase.expected)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package gmir
+
+import (
+	"github.com/codesoap/gmir/parser"
+	"github.com/gdamore/tcell/v2"
+)
+
+// FocusNext moves the focus to the next selectable and scrolls it into
+// view. If the focused selectable is not visible, the first visible one
+// is focused instead. After the last selectable, the first one is
+// focused. Returns false, if there is no selectable.
+func (v *View) FocusNext(screen tcell.Screen) bool {
+	start := v.line
+	if v.isVisible(screen, v.focus) {
+		start = v.focus + 1
+	}
+	for i := 0; i < len(v.lines); i++ {
+		line := (start + i) % len(v.lines)
+		if v.isSelectable(v.lines[line]) {
+			v.focusLine(screen, line)
+			return true
+		}
+	}
+	return false
+}
+
+// FocusPrev moves the focus to the previous selectable and scrolls it
+// into view. If the focused selectable is not visible, the last visible
+// one is focused instead. Before the first selectable, the last one is
+// focused. Returns false, if there is no selectable.
+func (v *View) FocusPrev(screen tcell.Screen) bool {
+	start := v.line
+	if v.isVisible(screen, v.focus) {
+		start = v.focus - 1
+	} else if rows := v.rows(screen); len(rows) > 0 {
+		start = rows[len(rows)-1].line
+	}
+	for i := 0; i < len(v.lines); i++ {
+		line := ((start-i)%len(v.lines) + len(v.lines)) % len(v.lines)
+		if v.isSelectable(v.lines[line]) {
+			v.focusLine(screen, line)
+			return true
+		}
+	}
+	return false
+}
+
+// Focus returns the index of the focused selectable. Returns false, if
+// nothing is focused.
+func (v View) Focus() (int, bool) {
+	return v.SelectableIndex(v.focus)
+}
+
+// ClearFocus removes the focus.
+func (v *View) ClearFocus() {
+	v.focus = -1
+}
+
+// focusedURL returns the URL of the focused link. Returns false, if no
+// link is focused.
+func (v View) focusedURL() (string, bool) {
+	if v.focus < 0 || v.focus >= len(v.lines) {
+		return "", false
+	}
+	link, isLink := v.lines[v.focus].(parser.LinkLine)
+	return link.ResolvedURL(), isLink
+}
+
+func (v *View) focusLine(screen tcell.Screen, line int) {
+	v.focus = line
+	if line < v.line || line == v.line && v.lineOffset > 0 {
+		v.ScrollToPosition(screen, line, 0)
+		return
+	}
+	for !v.isVisible(screen, line) {
+		previousLine, previousLineOffset := v.Position()
+		v.Scroll(screen, -1)
+		if v.line == previousLine && v.lineOffset == previousLineOffset {
+			break
+		}
+	}
+}
+
+// isVisible returns true, if the line at the given index is drawn to the
+// screen completely, or fills all of it.
+func (v View) isVisible(screen tcell.Screen, line int) bool {
+	rows := v.rows(screen)
+	if len(rows) == 0 || line < v.line || line == v.line && v.lineOffset > 0 {
+		return false
+	}
+	last := rows[len(rows)-1]
+	return line < last.line ||
+		line == last.line && (last.lineOffset == v.maxLineOffset(screen, line) || rows[0].line == line)
+}
@@ -0,0 +1,93 @@
+package gmir
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// focusDoc has links at the lines 0, 2 and 23. On a screen with five
+// rows of text, the last link is visible, when scrolled to line 19.
+var focusDoc = "=> gemini://a.example/ A\n" +
+	"text\n" +
+	"=> gemini://b.example/ B\n" +
+	strings.Repeat("text\n", 20) +
+	"=> gemini://c.example/ C\n" +
+	"end\n"
+
+var focusTestCases = []struct {
+	name  string
+	line  int    // The line scrolled to before moving the focus.
+	steps string // 'n' for FocusNext and 'p' for FocusPrev.
+	focus int
+	pos   int // The first displayed line afterwards.
+}{
+	{"first link", 0, "n", 0, 0},
+	{"next link", 0, "nn", 1, 0},
+	{"scroll down", 0, "nnn", 2, 19},
+	{"wrap to first", 0, "nnnn", 0, 0},
+	{"last visible link", 0, "p", 1, 0},
+	{"previous link", 0, "pp", 0, 0},
+	{"wrap to last", 0, "ppp", 2, 19},
+	{"scroll up", 0, "nnnp", 1, 2},
+	{"skip text", 10, "n", 2, 19},
+	{"skip text backwards", 10, "p", 1, 2},
+	{"wrap above screen", 19, "nn", 0, 0},
+}
+
+func TestFocus(t *testing.T) {
+	s := tcell.NewSimulationScreen("")
+	if err := s.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Fini()
+	s.SetSize(40, 6)
+	for _, testCase := range focusTestCases {
+		v, err := NewView(strings.NewReader(focusDoc), "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		v.ScrollToPosition(s, testCase.line, 0)
+		for _, step := range testCase.steps {
+			var ok bool
+			if step == 'n' {
+				ok = v.FocusNext(s)
+			} else {
+				ok = v.FocusPrev(s)
+			}
+			if !ok {
+				t.Errorf("Could not move focus for %s.", testCase.name)
+			}
+		}
+		focus, ok := v.Focus()
+		if !ok || focus != testCase.focus {
+			t.Errorf("Got focus %d for %s, expected %d.", focus, testCase.name, testCase.focus)
+		}
+		if line, _ := v.Position(); line != testCase.pos {
+			t.Errorf("Got line %d for %s, expected %d.", line, testCase.name, testCase.pos)
+		}
+		if !v.isVisible(s, v.focus) {
+			t.Errorf("Focused link is not visible for %s.", testCase.name)
+		}
+	}
+}
+
+func TestFocusWithoutLinks(t *testing.T) {
+	s := tcell.NewSimulationScreen("")
+	if err := s.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Fini()
+	s.SetSize(40, 6)
+	v, err := NewView(strings.NewReader("# Heading\ntext\n"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.FocusNext(s) || v.FocusPrev(s) {
+		t.Errorf("Focused a link in a document without links.")
+	}
+	if _, ok := v.Focus(); ok {
+		t.Errorf("Got a focus in a document without links.")
+	}
+}
@@ -3,16 +3,16 @@ package gmir
 import (
 	"fmt"
 	"io"
+	"net/url"
 	"regexp"
 
+	"github.com/codesoap/gmir/highlight"
 	"github.com/codesoap/gmir/parser"
-	"github.com/codesoap/gmir/selector"
+	"github.com/codesoap/gmir/readline"
 	"github.com/gdamore/tcell/v2"
 )
 
-// TODO: Select from search history.
 // TODO: Better name than lineOffset/LineOffset.
-// TODO: forward-/backward-link.
 // FIXME: Search term and scroll position are kept in two places. Make it one.
 
 type Mode int
@@ -22,6 +22,9 @@ const (
 	Regular       = Mode(iota)
 	Search        // Typing a search term.
 	ReverseSearch // Typing a search term for reverse search.
+	Select        // Typing a selector.
+	Filter        // Typing the filter of a link picker.
+	Command       // Typing a command.
 )
 
 const (
@@ -32,9 +35,14 @@ const (
 // A View represents the whole state related to a document, including
 // its content and scroll position.
 type View struct {
+	source parser.Document // The parsed input; empty for derived views like the TOC.
+
 	lines []parser.Line
 	line  int // Index in lines of the first displayed line.
 
+	// Syntax highlighting of preformatted lines by their index in lines.
+	syntax map[int][]highlight.Span
+
 	// Number of wrapped lines to skip within the first displayed line.
 	lineOffset int
 
@@ -45,11 +53,25 @@ type View struct {
 
 	selectable selectable
 	selector   string // Selector while it is being typed.
+	focus      int    // Index in lines of the focused selectable; -1 if none.
+
+	// Index of each selectable in the view a link picker was created
+	// from; nil for other views.
+	origins []int
 
 	Mode          Mode
-	Searchterm    string         // The search term while it is being typed.
-	Cursor        int            // Index of first byte of cursored rune in Searchterm. May be up to len(Searchterm).
-	Searchpattern *regexp.Regexp // The active search pattern.
+	Prompt        *readline.Editor // The editor of the search term or filter while it is being typed.
+	Searchpattern *regexp.Regexp   // The active search pattern.
+	searchStart   row              // The position, where the search was started.
+	literalSearch bool             // True, if search terms are not regular expressions.
+
+	// Positions stored by the name of their mark. The map is shared by
+	// copies of the view.
+	marks map[rune]Anchor
+
+	// The URL of the document, that links are resolved against; nil if
+	// unknown.
+	base *url.URL
 
 	// The title is displayed in the bar.
 	title string
@@ -57,19 +79,26 @@ type View struct {
 	// If not "", this info is displayed in the bar. Useful for infos like
 	// "Invalid search pattern" or "No match found".
 	Info string
+
+	loading bool // True while lines are still being added by AddLoaded.
 }
 
 func NewView(in io.Reader, title string) (View, error) {
-	lines, err := parser.Parse(in)
+	source, err := parser.ParseDocument(in)
 	if err != nil {
 		return View{}, err
-	} else if len(lines) == 0 {
+	}
+	lines := source.Lines()
+	if len(lines) == 0 {
 		return View{}, fmt.Errorf("given GMI is empty")
 	}
 	return View{
+		source:     source,
 		lines:      lines,
+		syntax:     syntaxSpans(source, 0),
 		Mode:       Regular,
 		selectable: link,
+		focus:      -1,
 		title:      title,
 	}, nil
 }
@@ -82,19 +111,72 @@ func (v View) TOCView() View {
 		lines:      v.headings(),
 		Mode:       Regular,
 		selectable: heading,
+		focus:      -1,
 		title:      "Table of contents",
 	}
 }
 
+// UpdateTOC replaces the lines of toc, which must be the TOCView of a
+// previous version of v, with the headings of v. Unlike creating a new
+// TOCView, this keeps the state of toc, like the position, the search
+// and the selector.
+func (v View) UpdateTOC(toc *View) {
+	toc.lines = v.headings()
+}
+
+// Title returns the title, that is displayed in the bar.
+func (v View) Title() string {
+	return v.title
+}
+
 func (v View) IsEmpty() bool {
 	return len(v.lines) == 0
 }
 
+// isVisited returns true, if url has been visited before. Visited links
+// are drawn with a distinct style.
+var isVisited = func(url string) bool { return false }
+
+// SetVisited sets the function, that tells if a URL has been visited.
+func SetVisited(f func(url string) bool) {
+	isVisited = f
+}
+
+// SetBase sets the URL of the document and resolves all links against
+// it. Links, that are added later, are resolved as well.
+func (v *View) SetBase(base string) error {
+	u, err := url.Parse(base)
+	if err != nil {
+		return err
+	}
+	v.base = u
+	v.resolveLinks(0)
+	return nil
+}
+
+// resolveLinks resolves the links starting at the given index in lines
+// against v.base.
+func (v *View) resolveLinks(from int) {
+	if v.base == nil {
+		return
+	}
+	for i := from; i < len(v.lines); i++ {
+		if link, isLink := v.lines[i].(parser.LinkLine); isLink {
+			v.lines[i] = link.Resolve(v.base)
+		}
+	}
+}
+
 // ShowURLs enables the display of URLs for link lines.
 func (v *View) ShowURLs() {
 	parser.ShowURLs = true
 }
 
+// URLsShown returns true, if URLs are displayed for link lines.
+func (v View) URLsShown() bool {
+	return parser.ShowURLs
+}
+
 // HideURLs disables the display of URLs for link lines.
 func (v *View) HideURLs() {
 	parser.ShowURLs = false
@@ -163,17 +245,8 @@ func (v View) maxLineOffset(screen tcell.Screen, line int) int {
 }
 
 func (v View) columnWidths(screenWidth int) (leftSpace, selectorColWidth, textWidth int) {
-	var selectableCount int
-	switch v.selectable {
-	case link:
-		selectableCount = len(v.links())
-	case heading:
-		selectableCount = len(v.headings())
-	default:
-		panic("unknown selectable")
-	}
-	if selectableCount > 0 {
-		selectorColWidth = len(selector.FromIndex(selectableCount-1)) + 1
+	if count := v.selectableCount(); count > 0 {
+		selectorColWidth = selectors.Width(count) + 1
 	}
 	if screenWidth >= maxTextWidth+selectorColWidth {
 		textWidth = maxTextWidth
@@ -0,0 +1,65 @@
+// Package highlight tokenizes the lines of preformatted blocks for
+// syntax highlighting. Highlighters are registered by language name and
+// looked up by the alt text of a block.
+package highlight
+
+import (
+	"strings"
+)
+
+type Kind int
+
+const (
+	Plain = Kind(iota)
+	Keyword
+	String
+	Comment
+	Number
+)
+
+// A Span marks the bytes from Start to End (exclusive) of a line as a
+// token of the given Kind. Bytes not covered by any span are Plain.
+type Span struct {
+	Start, End int
+	Kind       Kind
+}
+
+// A Highlighter tokenizes all lines of a preformatted block at once,
+// so that tokens may span multiple lines. The returned slice contains
+// the spans of every line, ordered by Start.
+type Highlighter interface {
+	Highlight(lines []string) [][]Span
+}
+
+var registry = make(map[string]Highlighter)
+
+// Register makes h available under the given language names. Names are
+// not case sensitive. Registering a name again replaces the previous
+// Highlighter.
+func Register(h Highlighter, names ...string) {
+	for _, name := range names {
+		registry[strings.ToLower(name)] = h
+	}
+}
+
+// Lookup returns the Highlighter for the language named by the first
+// word of altText. Returns nil, if no Highlighter is registered for it.
+func Lookup(altText string) Highlighter {
+	fields := strings.Fields(altText)
+	if len(fields) == 0 {
+		return nil
+	}
+	return registry[strings.ToLower(fields[0])]
+}
+
+// KindAt returns the Kind of the byte at index i, according to spans.
+func KindAt(spans []Span, i int) Kind {
+	for _, span := range spans {
+		if i < span.Start {
+			break
+		} else if i < span.End {
+			return span.Kind
+		}
+	}
+	return Plain
+}
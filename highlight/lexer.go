@@ -0,0 +1,199 @@
+package highlight
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// A Lexer is a simple Highlighter, that is sufficient for many C-like
+// and scripting languages.
+type Lexer struct {
+	Keywords      map[string]bool
+	LineComments  []string    // Prefixes starting comments, e.g. "//".
+	BlockComments [][2]string // Start and end of comments, e.g. "/*" and "*/".
+
+	// Quotes contains characters delimiting strings, which end at the
+	// end of the line. Backslashes escape the following character.
+	Quotes string
+
+	// MultilineQuotes contains characters delimiting raw strings, which
+	// may span multiple lines.
+	MultilineQuotes string
+}
+
+func init() {
+	Register(Lexer{
+		Keywords: words(`break case chan const continue default defer else
+			fallthrough for func go goto if import interface map package range
+			return select struct switch type var nil true false iota`),
+		LineComments:    []string{"//"},
+		BlockComments:   [][2]string{{"/*", "*/"}},
+		Quotes:          `"'`,
+		MultilineQuotes: "`",
+	}, "go", "golang")
+	Register(Lexer{
+		Keywords: words(`auto break case char const continue default do double
+			else enum extern float for goto if inline int long register return
+			short signed sizeof static struct switch typedef union unsigned void
+			volatile while NULL true false`),
+		LineComments:  []string{"//"},
+		BlockComments: [][2]string{{"/*", "*/"}},
+		Quotes:        `"'`,
+	}, "c", "h", "cpp", "c++")
+	Register(Lexer{
+		Keywords: words(`if then else elif fi for while until do done case esac
+			in function return local export readonly break continue exit`),
+		LineComments: []string{"#"},
+		Quotes:       `"'`,
+	}, "sh", "shell", "bash", "zsh", "ksh", "console")
+	Register(Lexer{
+		Keywords: words(`and as assert async await break class continue def del
+			elif else except finally for from global if import in is lambda
+			nonlocal not or pass raise return try while with yield None True False`),
+		LineComments: []string{"#"},
+		Quotes:       `"'`,
+	}, "python", "py")
+	Register(Lexer{
+		Keywords: words(`break case catch class const continue debugger default
+			delete do else export extends finally for function if import in
+			instanceof let new return super switch this throw try typeof var
+			void while yield async await null undefined true false`),
+		LineComments:    []string{"//"},
+		BlockComments:   [][2]string{{"/*", "*/"}},
+		Quotes:          `"'`,
+		MultilineQuotes: "`",
+	}, "javascript", "js", "typescript", "ts")
+	Register(Lexer{
+		Keywords: words(`true false null`),
+		Quotes:   `"`,
+	}, "json")
+}
+
+func words(s string) map[string]bool {
+	m := make(map[string]bool)
+	for _, word := range strings.Fields(s) {
+		m[word] = true
+	}
+	return m
+}
+
+func (l Lexer) Highlight(lines []string) [][]Span {
+	spans := make([][]Span, len(lines))
+	var end string // The delimiter of a token continued from the previous line.
+	var kind Kind
+	for i, line := range lines {
+		spans[i], end, kind = l.highlightLine(line, end, kind)
+	}
+	return spans
+}
+
+// highlightLine tokenizes line. If end is not empty, line starts within
+// a token of the given kind, that is terminated by end. The returned
+// end and kind describe the token, that continues on the next line.
+func (l Lexer) highlightLine(line, end string, kind Kind) ([]Span, string, Kind) {
+	spans := make([]Span, 0)
+	i := 0
+	if end != "" {
+		stop, closed := indexAfter(line, 0, end, false)
+		spans = append(spans, Span{0, stop, kind})
+		if !closed {
+			return spans, end, kind
+		}
+		i = stop
+	}
+	for i < len(line) {
+		rest := line[i:]
+		if hasAnyPrefix(rest, l.LineComments) {
+			spans = append(spans, Span{i, len(line), Comment})
+			break
+		}
+		if comment, found := l.blockCommentAt(rest); found {
+			stop, closed := indexAfter(line, i+len(comment[0]), comment[1], false)
+			spans = append(spans, Span{i, stop, Comment})
+			if !closed {
+				return spans, comment[1], Comment
+			}
+			i = stop
+			continue
+		}
+		c := line[i]
+		if strings.IndexByte(l.Quotes, c) >= 0 {
+			stop, _ := indexAfter(line, i+1, string(c), true)
+			spans = append(spans, Span{i, stop, String})
+			i = stop
+			continue
+		}
+		if strings.IndexByte(l.MultilineQuotes, c) >= 0 {
+			stop, closed := indexAfter(line, i+1, string(c), false)
+			spans = append(spans, Span{i, stop, String})
+			if !closed {
+				return spans, string(c), String
+			}
+			i = stop
+			continue
+		}
+		if isDigit(c) {
+			stop := i + 1
+			for stop < len(line) && (isWordByte(line[stop]) || line[stop] == '.') {
+				stop++
+			}
+			spans = append(spans, Span{i, stop, Number})
+			i = stop
+			continue
+		}
+		if isWordByte(c) {
+			stop := i + 1
+			for stop < len(line) && isWordByte(line[stop]) {
+				stop++
+			}
+			if l.Keywords[line[i:stop]] {
+				spans = append(spans, Span{i, stop, Keyword})
+			}
+			i = stop
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(rest)
+		i += size
+	}
+	return spans, "", Plain
+}
+
+func (l Lexer) blockCommentAt(text string) ([2]string, bool) {
+	for _, comment := range l.BlockComments {
+		if strings.HasPrefix(text, comment[0]) {
+			return comment, true
+		}
+	}
+	return [2]string{}, false
+}
+
+// indexAfter returns the index after the first occurrence of delim in
+// line, starting the search at start. If delim is not found, len(line)
+// and false are returned.
+func indexAfter(line string, start int, delim string, escapes bool) (int, bool) {
+	for i := start; i < len(line); i++ {
+		if escapes && line[i] == '\\' {
+			i++
+		} else if strings.HasPrefix(line[i:], delim) {
+			return i + len(delim), true
+		}
+	}
+	return len(line), false
+}
+
+func hasAnyPrefix(text string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(text, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || isDigit(c) || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
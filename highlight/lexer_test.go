@@ -0,0 +1,64 @@
+package highlight_test
+
+import (
+	"testing"
+
+	"github.com/codesoap/gmir/highlight"
+)
+
+var lexerTestCases = []struct {
+	language      string
+	input         []string
+	expectedSpans [][]highlight.Span
+}{
+	{
+		"go",
+		[]string{`return "a\"b" // c`},
+		[][]highlight.Span{{{0, 6, highlight.Keyword}, {7, 13, highlight.String}, {14, 18, highlight.Comment}}},
+	},
+	{
+		"Go example.go",
+		[]string{"x := `a", "b` + 42"},
+		[][]highlight.Span{{{5, 7, highlight.String}}, {{0, 2, highlight.String}, {5, 7, highlight.Number}}},
+	},
+	{
+		"c",
+		[]string{"/* a", "b */ int x1;"},
+		[][]highlight.Span{{{0, 4, highlight.Comment}}, {{0, 4, highlight.Comment}, {5, 8, highlight.Keyword}}},
+	},
+	{
+		"sh",
+		[]string{`echo "$1" # done`},
+		[][]highlight.Span{{{5, 9, highlight.String}, {10, 16, highlight.Comment}}},
+	},
+}
+
+func TestLexer(t *testing.T) {
+	for _, testCase := range lexerTestCases {
+		t.Logf("Testing with '%s'.", testCase.input)
+		highlighter := highlight.Lookup(testCase.language)
+		if highlighter == nil {
+			t.Errorf("No highlighter found for '%s'.", testCase.language)
+			continue
+		}
+		spans := highlighter.Highlight(testCase.input)
+		for i, lineSpans := range spans {
+			expected := testCase.expectedSpans[i]
+			if len(lineSpans) != len(expected) {
+				t.Errorf("Got %d spans in line %d, expected %d.", len(lineSpans), i, len(expected))
+				continue
+			}
+			for j, span := range lineSpans {
+				if span != expected[j] {
+					t.Errorf("Got span %v, expected %v.", span, expected[j])
+				}
+			}
+		}
+	}
+}
+
+func TestLookupUnknownLanguage(t *testing.T) {
+	if highlight.Lookup("ASCII art of a cat") != nil {
+		t.Errorf("Found highlighter for unknown language.")
+	}
+}
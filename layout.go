@@ -0,0 +1,73 @@
+package gmir
+
+import (
+	"github.com/codesoap/gmir/parser"
+	"github.com/gdamore/tcell/v2"
+)
+
+// A row identifies the part of a line, that is drawn in one row of the
+// screen.
+type row struct {
+	line       int // Index in lines.
+	lineOffset int // Number of the wrapped line within line.
+}
+
+// rows returns the rows, that are drawn to the screen, from top to
+// bottom. The bar is not included.
+func (v View) rows(screen tcell.Screen) []row {
+	screenWidth, screenHeight := screen.Size()
+	_, _, textWidth := v.columnWidths(screenWidth)
+	rows := make([]row, 0, screenHeight)
+	for i := v.line; i < len(v.lines) && len(rows) < screenHeight-1; i++ {
+		wraps := 0
+		if wrappable, isWrappable := v.lines[i].(parser.WrappableLine); isWrappable {
+			wraps = len(wrappable.WrapIndexes(textWidth))
+		}
+		for j := 0; j <= wraps && len(rows) < screenHeight-1; j++ {
+			if i != v.line || j >= v.lineOffset {
+				rows = append(rows, row{i, j})
+			}
+		}
+	}
+	return rows
+}
+
+// LineAt returns the index of the line and the number of the wrapped
+// line within it, that are drawn at the screen cell x, y. The selector
+// column belongs to the line. Returns false, if no line is drawn there.
+func (v View) LineAt(screen tcell.Screen, x, y int) (line, lineOffset int, ok bool) {
+	screenWidth, _ := screen.Size()
+	if x < v.leftEdge(screenWidth) || y < 0 {
+		return 0, 0, false
+	}
+	rows := v.rows(screen)
+	if y >= len(rows) {
+		return 0, 0, false
+	}
+	return rows[y].line, rows[y].lineOffset, true
+}
+
+// leftEdge returns the column, at which the selector column starts.
+func (v View) leftEdge(screenWidth int) int {
+	leftSpace, _, textWidth := v.columnWidths(screenWidth)
+	maxColOffset := v.maxLineWidth(textWidth) - textWidth
+	if v.ColOffset > maxColOffset {
+		return leftSpace - maxColOffset
+	}
+	return leftSpace - v.ColOffset
+}
+
+// SelectableIndex returns the index of the selectable, that is the line
+// at the given index. Returns false, if the line is not selectable.
+func (v View) SelectableIndex(line int) (int, bool) {
+	if line < 0 || line >= len(v.lines) || !v.isSelectable(v.lines[line]) {
+		return 0, false
+	}
+	index := 0
+	for _, l := range v.lines[:line] {
+		if v.isSelectable(l) {
+			index++
+		}
+	}
+	return index, true
+}
@@ -0,0 +1,85 @@
+package gmir
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/codesoap/gmir/parser"
+	"github.com/gdamore/tcell/v2"
+)
+
+// layoutDoc is drawn on a screen of 100x6 cells. The text column is 72
+// cells wide and starts at column 13, after eleven cells of space and
+// the selector column.
+var layoutDoc = "# Title\n" +
+	"=> gemini://a.example/ A\n" +
+	strings.Repeat("word ", 20) + "\n" + // Wrapped into two rows.
+	"=> gemini://b.example/ B\n" +
+	"```\n" + strings.Repeat("x", 90) + "\n```\n" +
+	"end\n"
+
+var lineAtTestCases = []struct {
+	name       string
+	line       int // The first displayed line.
+	colOffset  int
+	x, y       int
+	ok         bool
+	lineIndex  int
+	lineOffset int
+}{
+	{"first row", 0, 0, 13, 0, true, 0, 0},
+	{"selector column", 0, 0, 11, 1, true, 1, 0},
+	{"left space", 0, 0, 10, 1, false, 0, 0},
+	{"wrapped line", 0, 0, 20, 3, true, 2, 1},
+	{"right of the text", 0, 0, 99, 4, true, 3, 0},
+	{"bar", 0, 0, 13, 5, false, 0, 0},
+	{"above the screen", 0, 0, 13, -1, false, 0, 0},
+	{"scrolled", 2, 0, 13, 1, true, 2, 1},
+	{"past the end", 4, 0, 13, 2, false, 0, 0},
+	{"ColOffset", 0, 3, 8, 0, true, 0, 0},
+	{"left of ColOffset", 0, 3, 7, 0, false, 0, 0},
+	{"ColOffset beyond the widest line", 0, 100, 0, 0, true, 0, 0},
+}
+
+func TestLineAt(t *testing.T) {
+	defer func(show bool) { parser.ShowURLs = show }(parser.ShowURLs)
+	parser.ShowURLs = false
+	s := tcell.NewSimulationScreen("")
+	if err := s.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Fini()
+	s.SetSize(100, 6)
+	for _, testCase := range lineAtTestCases {
+		v, err := NewView(strings.NewReader(layoutDoc), "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		v.ScrollToPosition(s, testCase.line, 0)
+		v.ColOffset = testCase.colOffset
+		line, lineOffset, ok := v.LineAt(s, testCase.x, testCase.y)
+		if ok != testCase.ok || ok && (line != testCase.lineIndex || lineOffset != testCase.lineOffset) {
+			t.Errorf("Got %d, %d, %t for %s, expected %d, %d, %t.", line, lineOffset, ok,
+				testCase.name, testCase.lineIndex, testCase.lineOffset, testCase.ok)
+		}
+	}
+}
+
+func TestSelectableIndex(t *testing.T) {
+	v, err := NewView(strings.NewReader(layoutDoc), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[int]int{1: 0, 3: 1}
+	for line := -1; line <= len(v.lines); line++ {
+		index, ok := v.SelectableIndex(line)
+		want, isSelectable := expected[line]
+		if ok != isSelectable || ok && index != want {
+			t.Errorf("Got %d, %t for line %d, expected %d, %t.", index, ok, line, want, isSelectable)
+		}
+	}
+	toc := v.TOCView()
+	if index, ok := toc.SelectableIndex(0); !ok || index != 0 {
+		t.Errorf("Heading of the table of contents is not selectable.")
+	}
+}
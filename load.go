@@ -0,0 +1,111 @@
+package gmir
+
+import (
+	"io"
+	"time"
+
+	"github.com/codesoap/gmir/highlight"
+	"github.com/codesoap/gmir/parser"
+	"github.com/gdamore/tcell/v2"
+)
+
+// loadInterval is the time, for which lines are collected, before they
+// are posted to the screen.
+const loadInterval = 50 * time.Millisecond
+
+// An EventLoad carries lines, that have been read by Load. Add them to
+// a loading View with AddLoaded.
+type EventLoad struct {
+	tcell.EventTime
+	nodes []parser.LineNode
+	done  bool  // True, if the end of the input has been reached.
+	err   error // An error, that ended reading the input.
+}
+
+// Load reads GMI from in and posts the parsed lines as EventLoads to
+// screen, until the end of the input is reached. It blocks, so it
+// should be called in its own goroutine.
+func Load(in io.Reader, screen tcell.Screen) {
+	nodes := make(chan parser.LineNode)
+	var err error
+	go func() {
+		stream := parser.NewStream(in)
+		for {
+			node, ok := stream.Next()
+			if !ok {
+				break
+			}
+			nodes <- node
+		}
+		err = stream.Err()
+		close(nodes)
+	}()
+	ticker := time.NewTicker(loadInterval)
+	defer ticker.Stop()
+	batch := make([]parser.LineNode, 0)
+	for {
+		select {
+		case node, ok := <-nodes:
+			if !ok {
+				postEvent(screen, &EventLoad{nodes: batch, done: true, err: err})
+				return
+			}
+			batch = append(batch, node)
+		case <-ticker.C:
+			if len(batch) > 0 {
+				postEvent(screen, &EventLoad{nodes: batch})
+				batch = make([]parser.LineNode, 0)
+			}
+		}
+	}
+}
+
+func postEvent(screen tcell.Screen, ev *EventLoad) {
+	ev.SetEventNow()
+	for screen.PostEvent(ev) != nil {
+		// The event queue is full.
+		time.Sleep(loadInterval)
+	}
+}
+
+// NewLoadingView returns an empty View, that is filled by adding the
+// EventLoads posted by Load.
+func NewLoadingView(title string) View {
+	return View{
+		lines:      make([]parser.Line, 0),
+		syntax:     make(map[int][]highlight.Span),
+		Mode:       Regular,
+		selectable: link,
+		focus:      -1,
+		title:      title,
+		loading:    true,
+	}
+}
+
+// Loading returns true, if not all lines of v have been loaded yet.
+func (v View) Loading() bool {
+	return v.loading
+}
+
+// AddLoaded adds the lines of ev to v. Returns the error, that ended
+// loading, if any.
+func (v *View) AddLoaded(ev *EventLoad) error {
+	firstNewLine := len(v.lines)
+	for _, node := range ev.nodes {
+		v.lines = append(v.lines, v.source.Append(node)...)
+	}
+	v.resolveLinks(firstNewLine)
+	v.updateSyntax(firstNewLine)
+	if ev.done {
+		v.loading = false
+	}
+	return ev.err
+}
+
+// updateSyntax updates the syntax highlighting of all lines starting
+// with the block, that contains the line at index from.
+func (v *View) updateSyntax(from int) {
+	for i, spans := range syntaxSpans(v.source, from) {
+		v.syntax[i] = spans
+	}
+}
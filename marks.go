@@ -0,0 +1,51 @@
+package gmir
+
+import (
+	"github.com/gdamore/tcell/v2"
+)
+
+// LastJumpMark is the name of the mark, that holds the position before
+// the last jump. Jumps are scrolling to the top or bottom, to headings,
+// to search matches and to marks.
+const LastJumpMark = '\''
+
+// IsMarkName returns true, if name can be used as the name of a mark.
+// Valid names are the lowercase letters and LastJumpMark.
+func IsMarkName(name rune) bool {
+	return name >= 'a' && name <= 'z' || name == LastJumpMark
+}
+
+// SetMark stores the current position under name.
+func (v *View) SetMark(name rune) {
+	if v.marks == nil {
+		v.marks = make(map[rune]Anchor)
+	}
+	v.marks[name] = v.Anchor()
+}
+
+// JumpToMark scrolls to the position stored under name. Returns false,
+// if there is no such mark.
+func (v *View) JumpToMark(screen tcell.Screen, name rune) bool {
+	a, ok := v.marks[name]
+	if !ok {
+		return false
+	}
+	v.recordJump()
+	v.ScrollToAnchor(screen, a)
+	return true
+}
+
+// Marks returns the marks of v, so that they can be transferred to a
+// reloaded version of the document with SetMarks.
+func (v View) Marks() map[rune]Anchor {
+	return v.marks
+}
+
+// SetMarks replaces the marks of v.
+func (v *View) SetMarks(marks map[rune]Anchor) {
+	v.marks = marks
+}
+
+func (v *View) recordJump() {
+	v.SetMark(LastJumpMark)
+}
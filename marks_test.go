@@ -0,0 +1,124 @@
+package gmir
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// marksDoc returns a document with the lines "line 0" to "line 99",
+// preceded by the given lines.
+func marksDoc(t *testing.T, prefix string) View {
+	t.Helper()
+	var content strings.Builder
+	content.WriteString(prefix)
+	for i := 0; i < 100; i++ {
+		fmt.Fprintf(&content, "line %d\n", i)
+	}
+	v, err := NewView(strings.NewReader(content.String()), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func marksScreen(t *testing.T) tcell.Screen {
+	t.Helper()
+	s := tcell.NewSimulationScreen("")
+	if err := s.Init(); err != nil {
+		t.Fatal(err)
+	}
+	s.SetSize(40, 6)
+	return s
+}
+
+func expectLine(t *testing.T, v View, expected int, situation string) {
+	t.Helper()
+	if line, _ := v.Position(); line != expected {
+		t.Errorf("Got line %d %s, expected %d.", line, situation, expected)
+	}
+}
+
+func TestMarks(t *testing.T) {
+	s := marksScreen(t)
+	defer s.Fini()
+	v := marksDoc(t, "")
+	if v.JumpToMark(s, 'a') {
+		t.Errorf("Jumped to a mark, that is not set.")
+	}
+	v.ScrollToPosition(s, 40, 0)
+	v.SetMark('a')
+	v.ScrollToPosition(s, 60, 0)
+	v.SetMark('b')
+	v.ScrollToPosition(s, 10, 0)
+	if !v.JumpToMark(s, 'a') {
+		t.Fatalf("Could not jump to mark a.")
+	}
+	expectLine(t, v, 40, "at mark a")
+	if !v.JumpToMark(s, 'b') {
+		t.Fatalf("Could not jump to mark b.")
+	}
+	expectLine(t, v, 60, "at mark b")
+}
+
+func TestLastJumpMarkToggles(t *testing.T) {
+	s := marksScreen(t)
+	defer s.Fini()
+	v := marksDoc(t, "")
+	if v.JumpToMark(s, LastJumpMark) {
+		t.Errorf("Jumped back without a previous jump.")
+	}
+	v.ScrollToPosition(s, 40, 0)
+	v.SetMark('a')
+	v.ScrollToPosition(s, 10, 0)
+	v.JumpToMark(s, 'a')
+	for i, expected := range []int{10, 40, 10} {
+		if !v.JumpToMark(s, LastJumpMark) {
+			t.Fatalf("Could not jump back.")
+		}
+		expectLine(t, v, expected, fmt.Sprintf("after jumping back %d times", i+1))
+	}
+}
+
+func TestLastJumpMarkAfterSearch(t *testing.T) {
+	s := marksScreen(t)
+	defer s.Fini()
+	v := marksDoc(t, "")
+	v.ScrollToPosition(s, 5, 0)
+	v.Searchpattern = regexp.MustCompile("line 70")
+	if !v.ScrollDownToSearchMatch(s) {
+		t.Fatalf("Could not find search match.")
+	}
+	if line, _ := v.Position(); line == 5 {
+		t.Fatalf("Searching did not scroll.")
+	}
+	v.JumpToMark(s, LastJumpMark)
+	expectLine(t, v, 5, "after jumping back from the search match")
+
+	v.ScrollToBottom(s)
+	v.JumpToMark(s, LastJumpMark)
+	expectLine(t, v, 5, "after jumping back from the bottom")
+}
+
+func TestMarksSurviveReload(t *testing.T) {
+	s := marksScreen(t)
+	defer s.Fini()
+	v := marksDoc(t, "")
+	v.ScrollToPosition(s, 40, 0)
+	v.SetMark('a')
+	v.ScrollToTop(s)
+
+	reloaded := marksDoc(t, "# Added\nnew\nnew\n")
+	reloaded.SetMarks(v.Marks())
+	if !reloaded.JumpToMark(s, 'a') {
+		t.Fatalf("Mark a was lost.")
+	}
+	if text := reloaded.Anchor().Text; text != "line 40" {
+		t.Errorf("Got '%s' at mark a, expected 'line 40'.", text)
+	}
+	reloaded.JumpToMark(s, LastJumpMark)
+	expectLine(t, reloaded, 0, "after jumping back from mark a")
+}
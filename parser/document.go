@@ -0,0 +1,201 @@
+package parser
+
+// A Document is parsed GMI. Unlike the result of Parse, it retains the
+// source position and raw text of every line, as well as the
+// preformatting toggle lines.
+type Document struct {
+	Nodes []Node
+}
+
+// A Node is a top level element of a Document. It is either a LineNode
+// or a PreformattedBlock.
+type Node interface {
+	// SourceLines returns the numbers of the first and last source
+	// line of the node.
+	SourceLines() (first, last int)
+}
+
+// A LineNode is a single line of a Document.
+type LineNode struct {
+	Line   Line
+	Number int    // The number of the line in the source, starting at 1.
+	Raw    string // The line as found in the source, without line ending.
+}
+
+// A PreformattedBlock is a block of preformatted lines, enclosed by
+// preformatting toggle lines.
+type PreformattedBlock struct {
+	Open  LineNode   // The opening toggle line.
+	Lines []LineNode // The preformatted lines, excluding the toggle lines.
+
+	// The closing toggle line. Is nil, if the source ended within the
+	// block.
+	Close *LineNode
+}
+
+// A PreformattingToggleLine starts or ends a preformatted block. It
+// only occurs within a Document.
+type PreformattingToggleLine struct{ altText string }
+
+func (p PreformattingToggleLine) Text() string { return "```" + p.altText }
+
+// AltText returns the text following the toggle characters. It is
+// only meaningful for opening toggle lines.
+func (p PreformattingToggleLine) AltText() string { return p.altText }
+
+func (l LineNode) SourceLines() (first, last int) { return l.Number, l.Number }
+
+func (b PreformattedBlock) SourceLines() (first, last int) {
+	if b.Close != nil {
+		return b.Open.Number, b.Close.Number
+	} else if len(b.Lines) > 0 {
+		return b.Open.Number, b.Lines[len(b.Lines)-1].Number
+	}
+	return b.Open.Number, b.Open.Number
+}
+
+// AltText returns the alt text of the opening toggle line.
+func (b PreformattedBlock) AltText() string {
+	return b.Open.Line.(PreformattingToggleLine).AltText()
+}
+
+// Append adds node, which must be the next line of the source, to d.
+// Preformatting toggle lines and preformatted lines are added to
+// PreformattedBlocks. It returns the lines, that node adds to the result
+// of d.Lines().
+func (d *Document) Append(node LineNode) []Line {
+	block, inBlock := d.openBlock()
+	switch node.Line.(type) {
+	case PreformattingToggleLine:
+		if inBlock {
+			block.Close = &node
+			d.Nodes[len(d.Nodes)-1] = block
+			return nil
+		}
+		block = PreformattedBlock{Open: node}
+		d.Nodes = append(d.Nodes, block)
+		if altText := block.AltText(); altText != "" {
+			return []Line{AltTextLine{altText}}
+		}
+		return nil
+	case PreformattedLine:
+		if inBlock {
+			block.Lines = append(block.Lines, node)
+			d.Nodes[len(d.Nodes)-1] = block
+			if AltTextOnly && block.AltText() != "" {
+				return nil
+			}
+			return []Line{node.Line}
+		}
+	}
+	d.Nodes = append(d.Nodes, node)
+	return []Line{node.Line}
+}
+
+// openBlock returns the last node of d, if it is a PreformattedBlock,
+// that has not been closed yet.
+func (d Document) openBlock() (PreformattedBlock, bool) {
+	if len(d.Nodes) == 0 {
+		return PreformattedBlock{}, false
+	}
+	block, isBlock := d.Nodes[len(d.Nodes)-1].(PreformattedBlock)
+	return block, isBlock && block.Close == nil
+}
+
+// RawLines returns the source lines of d, as they were read.
+func (d Document) RawLines() []string {
+	raw := make([]string, 0, len(d.Nodes))
+	for _, node := range d.Nodes {
+		switch n := node.(type) {
+		case LineNode:
+			raw = append(raw, n.Raw)
+		case PreformattedBlock:
+			raw = append(raw, n.Open.Raw)
+			for _, l := range n.Lines {
+				raw = append(raw, l.Raw)
+			}
+			if n.Close != nil {
+				raw = append(raw, n.Close.Raw)
+			}
+		}
+	}
+	return raw
+}
+
+// Lines returns the lines of d, excluding preformatting toggle lines.
+// Preformatted blocks with an alt text are preceded by an AltTextLine.
+// If AltTextOnly is true, the preformatted lines of these blocks are
+// omitted.
+func (d Document) Lines() []Line {
+	lines := make([]Line, 0, len(d.Nodes))
+	for _, node := range d.Nodes {
+		switch n := node.(type) {
+		case LineNode:
+			lines = append(lines, n.Line)
+		case PreformattedBlock:
+			if altText := n.AltText(); altText != "" {
+				lines = append(lines, AltTextLine{altText})
+				if AltTextOnly {
+					continue
+				}
+			}
+			for _, l := range n.Lines {
+				lines = append(lines, l.Line)
+			}
+		}
+	}
+	return lines
+}
+
+// LineIndex returns the index in the result of d.Lines() of the line,
+// that displays the source line with the given number. Preformatting
+// toggle lines are mapped to the line following them and preformatted
+// lines, that are omitted because of AltTextOnly, to the AltTextLine of
+// their block. Numbers beyond the end are mapped to the last line.
+func (d Document) LineIndex(number int) int {
+	index := 0
+	for _, node := range d.Nodes {
+		if _, last := node.SourceLines(); last < number {
+			index += lineCount(node)
+			continue
+		}
+		block, isBlock := node.(PreformattedBlock)
+		if !isBlock {
+			return index
+		}
+		// The lines of the block are omitted, if hidden is true.
+		hidden := AltTextOnly && block.AltText() != ""
+		if block.AltText() != "" {
+			if number <= block.Open.Number || hidden && (block.Close == nil || number < block.Close.Number) {
+				return index
+			}
+			index++
+		}
+		for i := 0; i < len(block.Lines) && !hidden; i++ {
+			if block.Lines[i].Number >= number {
+				return index
+			}
+			index++
+		}
+		break // The closing toggle line is mapped to the following line.
+	}
+	if count := len(d.Lines()); index >= count && count > 0 {
+		return count - 1
+	}
+	return index
+}
+
+// lineCount returns the number of lines, that node adds to the result
+// of Lines().
+func lineCount(node Node) int {
+	switch n := node.(type) {
+	case PreformattedBlock:
+		if n.AltText() == "" {
+			return len(n.Lines)
+		} else if AltTextOnly {
+			return 1
+		}
+		return 1 + len(n.Lines)
+	}
+	return 1
+}
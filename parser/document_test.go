@@ -0,0 +1,126 @@
+package parser_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/codesoap/gmir/parser"
+)
+
+func TestParseDocument(t *testing.T) {
+	input := "# Title\n=> /a.gmi\tA\n```go  \nfmt.Println()\n```\n* item\n```\nunterminated"
+	doc, err := parser.ParseDocument(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Could not parse input: %v", err)
+	}
+	if len(doc.Nodes) != 5 {
+		t.Fatalf("Found %d nodes instead of five.", len(doc.Nodes))
+	}
+
+	link, ok := doc.Nodes[1].(parser.LineNode)
+	if !ok {
+		t.Fatalf("Second node is not a line node.")
+	}
+	if link.Number != 2 || link.Raw != "=> /a.gmi\tA" {
+		t.Errorf("Got line %d with raw text '%s'.", link.Number, link.Raw)
+	}
+	if l, ok := link.Line.(parser.LinkLine); !ok || l.URL() != "/a.gmi" || l.Name() != "A" {
+		t.Errorf("Link line was not parsed correctly.")
+	}
+
+	block, ok := doc.Nodes[2].(parser.PreformattedBlock)
+	if !ok {
+		t.Fatalf("Third node is not a preformatted block.")
+	}
+	if block.AltText() != "go" {
+		t.Errorf("Got alt text '%s', expected 'go'.", block.AltText())
+	}
+	if first, last := block.SourceLines(); first != 3 || last != 5 {
+		t.Errorf("Got source lines %d to %d, expected 3 to 5.", first, last)
+	}
+	if len(block.Lines) != 1 || block.Lines[0].Number != 4 {
+		t.Errorf("Preformatted lines were not parsed correctly.")
+	}
+
+	unterminated, ok := doc.Nodes[4].(parser.PreformattedBlock)
+	if !ok {
+		t.Fatalf("Last node is not a preformatted block.")
+	}
+	if unterminated.Close != nil {
+		t.Errorf("Unterminated block has a closing toggle line.")
+	}
+	if first, last := unterminated.SourceLines(); first != 7 || last != 8 {
+		t.Errorf("Got source lines %d to %d, expected 7 to 8.", first, last)
+	}
+
+	lines := doc.Lines()
+	if len(lines) != 6 {
+		t.Fatalf("Found %d lines instead of six.", len(lines))
+	}
+	if altText, ok := lines[2].(parser.AltTextLine); !ok || altText.Text() != "go" {
+		t.Errorf("Preformatted block is not preceded by its alt text.")
+	}
+	parser.AltTextOnly = true
+	defer func() { parser.AltTextOnly = false }()
+	if lines := doc.Lines(); len(lines) != 5 {
+		t.Errorf("Found %d lines instead of five with AltTextOnly.", len(lines))
+	}
+}
+
+func TestRawLines(t *testing.T) {
+	input := "# Title\n```go  \nfmt.Println()\n```\n* item\n```\nunterminated"
+	doc, err := parser.ParseDocument(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Could not parse input: %v", err)
+	}
+	if raw := strings.Join(doc.RawLines(), "\n"); raw != input {
+		t.Errorf("Got raw lines '%s', expected '%s'.", raw, input)
+	}
+}
+
+var lineIndexTestCases = []struct {
+	number      int
+	altTextOnly bool
+	expected    int
+}{
+	{1, false, 0},
+	{2, false, 1}, // Opening toggle line with alt text.
+	{3, false, 2},
+	{4, false, 3}, // Closing toggle line.
+	{5, false, 3},
+	{6, false, 4}, // Opening toggle line without alt text.
+	{8, false, 5}, // Adjacent toggle lines.
+	{9, false, 5},
+	{11, false, 6},
+	{12, false, 6},
+	{99, false, 6},
+	{3, true, 1}, // Omitted preformatted line.
+	{4, true, 2},
+	{12, true, 5},
+}
+
+func TestLineIndex(t *testing.T) {
+	input := "# Title\n```go\nfmt.Println()\n```\n* item\n```\na\n```\n```\nb\n```\ntext"
+	doc, err := parser.ParseDocument(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Could not parse input: %v", err)
+	}
+	defer func() { parser.AltTextOnly = false }()
+	for _, testCase := range lineIndexTestCases {
+		parser.AltTextOnly = testCase.altTextOnly
+		if got := doc.LineIndex(testCase.number); got != testCase.expected {
+			t.Errorf("Got index %d for line %d (AltTextOnly: %t), expected %d.",
+				got, testCase.number, testCase.altTextOnly, testCase.expected)
+		}
+	}
+}
+
+func TestLineIndexOfClosingToggleAtEnd(t *testing.T) {
+	doc, err := parser.ParseDocument(strings.NewReader("text\n```\nx\n```"))
+	if err != nil {
+		t.Fatalf("Could not parse input: %v", err)
+	}
+	if got := doc.LineIndex(4); got != 1 {
+		t.Errorf("Got index %d for the closing toggle line, expected 1.", got)
+	}
+}
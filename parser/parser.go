@@ -1,20 +1,23 @@
 package parser
 
 import (
-	"bufio"
 	"fmt"
 	"io"
+	"net/url"
 	"regexp"
 	"strings"
 	"unicode/utf8"
 
 	"github.com/mattn/go-runewidth"
-	"golang.org/x/text/unicode/norm"
 )
 
 var (
 	ShowURLs = true // Always include URLs in the text of LinkLines.
 
+	// Omit the preformatted lines of blocks, that have an alt text, from
+	// Document.Lines(), leaving only their AltTextLine.
+	AltTextOnly = false
+
 	reLinkLine                = regexp.MustCompile(`^=>\s*(\S+)(\s+(.+))?\s*$`)
 	rePreformattingToggleLine = regexp.MustCompile("^```")
 	reHeading1Line            = regexp.MustCompile(`^#\s*(.+)\s*$`)
@@ -35,20 +38,25 @@ type WrappableLine interface {
 }
 
 type TextLine struct{ text string }
-type LinkLine struct{ url, name string }
+type LinkLine struct {
+	url, name string
+	resolved  string // The URL resolved by Resolve; empty, if not resolved.
+	scheme    Scheme // The class of the resolved URL.
+}
 type PreformattedLine struct{ text string }
 type Heading1Line struct{ text string }
 type Heading2Line struct{ text string }
 type Heading3Line struct{ text string }
 type ListLine struct{ text string }
 type QuoteLine struct{ text string }
+type AltTextLine struct{ text string } // The alt text of a preformatted block.
 
 func (t TextLine) Text() string { return t.text }
 func (l LinkLine) Text() string {
 	if l.name == "" {
-		return fmt.Sprintf("=> %s", l.url)
+		return fmt.Sprintf("=> %s", l.ResolvedURL())
 	} else if ShowURLs {
-		return fmt.Sprintf("=> %s (%s)", l.name, l.url)
+		return fmt.Sprintf("=> %s (%s)", l.name, l.ResolvedURL())
 	}
 	return fmt.Sprintf("=> %s", l.name)
 }
@@ -58,6 +66,7 @@ func (h Heading2Line) Text() string     { return "## " + h.text }
 func (h Heading3Line) Text() string     { return "### " + h.text }
 func (l ListLine) Text() string         { return "* " + l.text }
 func (q QuoteLine) Text() string        { return "> " + q.text }
+func (a AltTextLine) Text() string      { return a.text }
 
 func (t TextLine) WrapIndexes(width int) []int { return wrapIndexes(t.Text(), width, t.IndentWidth()) }
 func (l LinkLine) WrapIndexes(width int) []int { return wrapIndexes(l.Text(), width, l.IndentWidth()) }
@@ -72,6 +81,9 @@ func (h Heading3Line) WrapIndexes(width int) []int {
 }
 func (l ListLine) WrapIndexes(width int) []int  { return wrapIndexes(l.Text(), width, l.IndentWidth()) }
 func (q QuoteLine) WrapIndexes(width int) []int { return wrapIndexes(q.Text(), width, q.IndentWidth()) }
+func (a AltTextLine) WrapIndexes(width int) []int {
+	return wrapIndexes(a.Text(), width, a.IndentWidth())
+}
 
 func (t TextLine) IndentWidth() int     { return 0 }
 func (l LinkLine) IndentWidth() int     { return 3 }
@@ -80,8 +92,38 @@ func (h Heading2Line) IndentWidth() int { return 3 }
 func (h Heading3Line) IndentWidth() int { return 4 }
 func (l ListLine) IndentWidth() int     { return 2 }
 func (q QuoteLine) IndentWidth() int    { return 2 }
+func (a AltTextLine) IndentWidth() int  { return 0 }
+
+func (l LinkLine) URL() string  { return l.url }
+func (l LinkLine) Name() string { return l.name } // Empty, if the link has no name.
+
+// Resolve returns a copy of l, whose URL is resolved against base. l is
+// returned unchanged, if its URL cannot be parsed.
+func (l LinkLine) Resolve(base *url.URL) LinkLine {
+	ref, err := url.Parse(l.url)
+	if err != nil {
+		return l
+	}
+	l.resolved = base.ResolveReference(ref).String()
+	l.scheme = schemeOf(l.resolved)
+	return l
+}
 
-func (l LinkLine) URL() string { return l.url }
+// ResolvedURL returns the URL of l resolved by Resolve or URL(), if l
+// has not been resolved.
+func (l LinkLine) ResolvedURL() string {
+	if l.resolved == "" {
+		return l.url
+	}
+	return l.resolved
+}
+
+// Content returns the text of a line without its line type prefix.
+func (h Heading1Line) Content() string { return h.text }
+func (h Heading2Line) Content() string { return h.text }
+func (h Heading3Line) Content() string { return h.text }
+func (l ListLine) Content() string     { return l.text }
+func (q QuoteLine) Content() string    { return q.text }
 
 func wrapIndexes(text string, width, indent int) []int {
 	if indent < 0 {
@@ -173,50 +215,47 @@ func lastIndexWithinWidth(text string, width int) int {
 }
 
 // Parse parses the GMI from the given reader. All text will be
-// normalized to the NFC form.
+// normalized to the NFC form. Preformatting toggle lines are not part of
+// the result; use ParseDocument to retain them.
 func Parse(in io.Reader) ([]Line, error) {
-	preformatted := false
-	out := make([]Line, 0)
-	nfcIn := norm.NFC.Reader(in)
-	s := bufio.NewScanner(nfcIn)
-	for s.Scan() {
-		// TODO: A replacing io.Reader would probably be more performant
-		//       than strings.ReplaceAll().
-		// Tabs are replaced, because they don't work well with tcell.
-		line := strings.ReplaceAll(s.Text(), "\t", "    ")
-		if rePreformattingToggleLine.MatchString(line) {
-			preformatted = !preformatted
-			continue
-		}
-		if preformatted {
-			out = append(out, PreformattedLine{line})
-			continue
-		}
-		if m := reLinkLine.FindStringSubmatch(line); m != nil {
-			out = append(out, LinkLine{m[1], m[3]})
-			continue
-		}
-		if m := reHeading3Line.FindStringSubmatch(line); m != nil {
-			out = append(out, Heading3Line{m[1]})
-			continue
-		}
-		if m := reHeading2Line.FindStringSubmatch(line); m != nil {
-			out = append(out, Heading2Line{m[1]})
-			continue
-		}
-		if m := reHeading1Line.FindStringSubmatch(line); m != nil {
-			out = append(out, Heading1Line{m[1]})
-			continue
-		}
-		if m := reListLine.FindStringSubmatch(line); m != nil {
-			out = append(out, ListLine{m[1]})
-			continue
-		}
-		if m := reQuoteLine.FindStringSubmatch(line); m != nil {
-			out = append(out, QuoteLine{m[1]})
-			continue
+	doc, err := ParseDocument(in)
+	return doc.Lines(), err
+}
+
+// ParseDocument parses the GMI from the given reader into a Document.
+// All text will be normalized to the NFC form.
+func ParseDocument(in io.Reader) (Document, error) {
+	var doc Document
+	stream := NewStream(in)
+	for {
+		node, ok := stream.Next()
+		if !ok {
+			return doc, stream.Err()
 		}
-		out = append(out, TextLine{strings.TrimSpace(line)})
+		doc.Append(node)
+	}
+}
+
+// parseLine parses a single line, that is not within a preformatted
+// block.
+func parseLine(line string) Line {
+	if m := reLinkLine.FindStringSubmatch(line); m != nil {
+		return LinkLine{url: m[1], name: m[3], scheme: schemeOf(m[1])}
+	}
+	if m := reHeading3Line.FindStringSubmatch(line); m != nil {
+		return Heading3Line{m[1]}
+	}
+	if m := reHeading2Line.FindStringSubmatch(line); m != nil {
+		return Heading2Line{m[1]}
+	}
+	if m := reHeading1Line.FindStringSubmatch(line); m != nil {
+		return Heading1Line{m[1]}
+	}
+	if m := reListLine.FindStringSubmatch(line); m != nil {
+		return ListLine{m[1]}
+	}
+	if m := reQuoteLine.FindStringSubmatch(line); m != nil {
+		return QuoteLine{m[1]}
 	}
-	return out, s.Err()
+	return TextLine{strings.TrimSpace(line)}
 }
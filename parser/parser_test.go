@@ -1,6 +1,7 @@
 package parser_test
 
 import (
+	"net/url"
 	"strings"
 	"testing"
 
@@ -66,6 +67,108 @@ var wrapTestCases = []struct {
 	},
 }
 
+// resolveTestCases are cases where the URL of a link line is resolved
+// against base.
+var resolveTestCases = []struct {
+	base     string
+	url      string
+	expected string
+}{
+	{"gemini://example.org/dir/index.gmi", "page.gmi", "gemini://example.org/dir/page.gmi"},
+	{"gemini://example.org/dir/index.gmi", "//other.org/page.gmi", "gemini://other.org/page.gmi"},
+	{"gemini://example.org/dir/index.gmi", "../up.gmi", "gemini://example.org/up.gmi"},
+	{"gemini://example.org/dir/index.gmi", "../../up.gmi", "gemini://example.org/up.gmi"},
+	{"gemini://example.org/dir/index.gmi", "/abs.gmi", "gemini://example.org/abs.gmi"},
+	{"gemini://example.org/dir/index.gmi", "?q=1", "gemini://example.org/dir/index.gmi?q=1"},
+	{"gemini://example.org/dir/index.gmi", "https://example.com/", "https://example.com/"},
+	{"gemini://example.org/dir/index.gmi", "mailto:user@example.org", "mailto:user@example.org"},
+	{"gemini://example.org/dir/index.gmi", "%zz", "%zz"}, // Unparsable.
+	{"file:///docs/index.gmi", "../up.gmi", "file:///up.gmi"},
+	{"file:///docs/index.gmi", "//host/page.gmi", "file://host/page.gmi"},
+}
+
+func TestResolve(t *testing.T) {
+	for _, testCase := range resolveTestCases {
+		base, err := url.Parse(testCase.base)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines, err := parser.Parse(strings.NewReader("=> " + testCase.url + " Name"))
+		if err != nil {
+			t.Fatalf("Could not parse input: %v", err)
+		}
+		link, ok := lines[0].(parser.LinkLine)
+		if !ok {
+			t.Fatalf("Given line is not a link.")
+		}
+		if link.ResolvedURL() != testCase.url {
+			t.Errorf("Got '%s' before resolving, expected '%s'.", link.ResolvedURL(), testCase.url)
+		}
+		resolved := link.Resolve(base)
+		if resolved.ResolvedURL() != testCase.expected {
+			t.Errorf("Got '%s' for '%s' in '%s', expected '%s'.",
+				resolved.ResolvedURL(), testCase.url, testCase.base, testCase.expected)
+		}
+		if resolved.URL() != testCase.url {
+			t.Errorf("Got URL '%s', expected '%s'.", resolved.URL(), testCase.url)
+		}
+	}
+}
+
+// schemeTestCases are cases where the scheme of a link is classified
+// before and after resolving it against base.
+var schemeTestCases = []struct {
+	url      string
+	base     string
+	parsed   parser.Scheme
+	resolved parser.Scheme
+	marker   string // The marker after resolving.
+}{
+	{"gemini://example.org/", "gemini://example.org/", parser.SchemeGemini, parser.SchemeGemini, ""},
+	{"GEMINI://example.org/", "gemini://example.org/", parser.SchemeGemini, parser.SchemeGemini, ""},
+	{"https://example.org/", "gemini://example.org/", parser.SchemeHTTP, parser.SchemeHTTP, "[www]"},
+	{"http://example.org/", "gemini://example.org/", parser.SchemeHTTP, parser.SchemeHTTP, "[www]"},
+	{"gopher://example.org/", "gemini://example.org/", parser.SchemeGopher, parser.SchemeGopher, "[gopher]"},
+	{"mailto:user@example.org", "gemini://example.org/", parser.SchemeMailto, parser.SchemeMailto, "[mail]"},
+	{"file:///page.gmi", "gemini://example.org/", parser.SchemeFile, parser.SchemeFile, ""},
+	{"spartan://example.org/", "gemini://example.org/", parser.SchemeUnknown, parser.SchemeUnknown, "[?]"},
+	{"%zz", "gemini://example.org/", parser.SchemeUnknown, parser.SchemeUnknown, "[?]"},
+	{"page.gmi", "gemini://example.org/", parser.SchemeRelative, parser.SchemeGemini, ""},
+	{"//example.org/", "https://example.org/", parser.SchemeRelative, parser.SchemeHTTP, "[www]"},
+	{"page.gmi", "file:///docs/index.gmi", parser.SchemeRelative, parser.SchemeFile, ""},
+}
+
+func TestScheme(t *testing.T) {
+	for _, testCase := range schemeTestCases {
+		base, err := url.Parse(testCase.base)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines, err := parser.Parse(strings.NewReader("=> " + testCase.url + " Name"))
+		if err != nil {
+			t.Fatalf("Could not parse input: %v", err)
+		}
+		link, ok := lines[0].(parser.LinkLine)
+		if !ok {
+			t.Fatalf("Given line is not a link.")
+		}
+		if link.Scheme() != testCase.parsed {
+			t.Errorf("Got scheme %d for '%s', expected %d.", link.Scheme(), testCase.url, testCase.parsed)
+		}
+		link = link.Resolve(base)
+		if link.Scheme() != testCase.resolved {
+			t.Errorf("Got scheme %d for '%s' in '%s', expected %d.",
+				link.Scheme(), testCase.url, testCase.base, testCase.resolved)
+		}
+		if marker := link.Scheme().Marker(); marker != testCase.marker {
+			t.Errorf("Got marker '%s' for '%s', expected '%s'.", marker, testCase.url, testCase.marker)
+		}
+		if link.Text() != "=> Name" && link.Text() != "=> Name ("+link.ResolvedURL()+")" {
+			t.Errorf("Got '%s' as text of '%s', expected no marker.", link.Text(), testCase.url)
+		}
+	}
+}
+
 func TestWrap(t *testing.T) {
 	for _, testCase := range wrapTestCases {
 		t.Logf("Testing with '%s'.", testCase.input)
@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"net/url"
+	"strings"
+)
+
+// A Scheme is the class of the URL of a link.
+type Scheme int
+
+const (
+	SchemeGemini = Scheme(iota)
+	SchemeHTTP   // http and https.
+	SchemeGopher
+	SchemeMailto
+	SchemeFile
+	SchemeRelative // URLs without a scheme.
+	SchemeUnknown
+)
+
+// schemeMarkers contains the markers, that are displayed next to
+// links, whose URL leads out of Geminispace.
+var schemeMarkers = map[Scheme]string{
+	SchemeHTTP:    "[www]",
+	SchemeGopher:  "[gopher]",
+	SchemeMailto:  "[mail]",
+	SchemeUnknown: "[?]",
+}
+
+// Marker returns the marker, that is displayed next to links of scheme
+// s, or an empty string, if there is none.
+func (s Scheme) Marker() string {
+	return schemeMarkers[s]
+}
+
+// Scheme returns the class of the resolved URL of l.
+func (l LinkLine) Scheme() Scheme {
+	return l.scheme
+}
+
+// schemeOf returns the class of rawURL.
+func schemeOf(rawURL string) Scheme {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return SchemeUnknown
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "gemini":
+		return SchemeGemini
+	case "http", "https":
+		return SchemeHTTP
+	case "gopher":
+		return SchemeGopher
+	case "mailto":
+		return SchemeMailto
+	case "file":
+		return SchemeFile
+	case "":
+		return SchemeRelative
+	}
+	return SchemeUnknown
+}
@@ -0,0 +1,52 @@
+package parser
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// A Stream parses GMI line by line. Unlike Parse, it allows using the
+// first lines before all input has been read.
+type Stream struct {
+	scanner      *bufio.Scanner
+	number       int  // The number of the last scanned line.
+	preformatted bool // True while within a preformatted block.
+}
+
+// NewStream returns a Stream reading from in. All text will be
+// normalized to the NFC form.
+func NewStream(in io.Reader) *Stream {
+	return &Stream{scanner: bufio.NewScanner(norm.NFC.Reader(in))}
+}
+
+// Next blocks until the next line is read and returns it. Preformatting
+// toggle lines are included. The returned bool is false, if the end of
+// the input has been reached or an error occurred; use Err to tell the
+// two apart.
+func (s *Stream) Next() (LineNode, bool) {
+	if !s.scanner.Scan() {
+		return LineNode{}, false
+	}
+	s.number++
+	raw := s.scanner.Text()
+	// TODO: A replacing io.Reader would probably be more performant
+	//       than strings.ReplaceAll().
+	// Tabs are replaced, because they don't work well with tcell.
+	line := strings.ReplaceAll(raw, "\t", "    ")
+	if rePreformattingToggleLine.MatchString(line) {
+		s.preformatted = !s.preformatted
+		return LineNode{PreformattingToggleLine{strings.TrimSpace(line[3:])}, s.number, raw}, true
+	}
+	if s.preformatted {
+		return LineNode{PreformattedLine{line}, s.number, raw}, true
+	}
+	return LineNode{parseLine(line), s.number, raw}, true
+}
+
+// Err returns the first error, that occurred while reading.
+func (s *Stream) Err() error {
+	return s.scanner.Err()
+}
@@ -0,0 +1,87 @@
+package gmir
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/codesoap/gmir/parser"
+)
+
+// LinkPickerView returns a view of the links of v, whose name or URL
+// fuzzy-match filter, with the best matches first. The best match is
+// focused. This view is suitable for picking a link.
+func (v View) LinkPickerView(filter string) View {
+	type match struct {
+		link   parser.LinkLine
+		origin int
+		score  int
+	}
+	matches := make([]match, 0)
+	for i, link := range v.links() {
+		nameScore, nameMatches := fuzzyScore(filter, link.Name())
+		urlScore, urlMatches := fuzzyScore(filter, link.ResolvedURL())
+		if !nameMatches && !urlMatches {
+			continue
+		} else if !nameMatches || urlMatches && urlScore > nameScore {
+			nameScore = urlScore
+		}
+		matches = append(matches, match{link, i, nameScore})
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+	lines := make([]parser.Line, len(matches))
+	origins := make([]int, len(matches))
+	for i, m := range matches {
+		lines[i] = m.link
+		origins[i] = m.origin
+	}
+	picker := View{
+		lines:      lines,
+		Mode:       Regular,
+		selectable: link,
+		focus:      -1,
+		origins:    origins,
+		title:      "Links",
+	}
+	if len(lines) > 0 {
+		picker.focus = 0
+	}
+	return picker
+}
+
+// OriginalIndex returns the index, that the link with the given index
+// has in the view, from which the link picker v was created. For views
+// other than link pickers, index is returned unchanged.
+func (v View) OriginalIndex(index int) int {
+	if v.origins == nil || index < 0 || index >= len(v.origins) {
+		return index
+	}
+	return v.origins[index]
+}
+
+// fuzzyScore returns how well pattern matches text. The runes of
+// pattern must appear in text in the same order, ignoring case.
+// Consecutive runes and runes at the start of words score higher.
+// Returns false, if pattern does not match.
+func fuzzyScore(pattern, text string) (int, bool) {
+	p := []rune(strings.ToLower(pattern))
+	score, matched := 0, 0
+	previous, previousMatched := ' ', false
+	for _, r := range strings.ToLower(text) {
+		isMatch := matched < len(p) && r == p[matched]
+		if isMatch {
+			score++
+			if previousMatched {
+				score += 2
+			}
+			if !unicode.IsLetter(previous) && !unicode.IsDigit(previous) {
+				score++
+			}
+			matched++
+		}
+		previous, previousMatched = r, isMatch
+	}
+	return score, matched == len(p)
+}
@@ -0,0 +1,104 @@
+package gmir
+
+import (
+	"strings"
+	"testing"
+)
+
+// fuzzyScoreTestCases list texts from the best to the worst match of
+// pattern.
+var fuzzyScoreTestCases = []struct {
+	pattern string
+	texts   []string
+}{
+	{"gem", []string{"gemini", "g_e_m", "a gxexm"}},
+	{"cap", []string{"capsule", "xcapsule", "xcxaxp"}},
+	{"pie", []string{"Pie", "Pineapple", "Apple pie"}},
+	{"ABC", []string{"abc", "a b c", "xaxbxc"}},
+}
+
+func TestFuzzyScoreRanking(t *testing.T) {
+	for _, testCase := range fuzzyScoreTestCases {
+		previousScore := 0
+		for i, text := range testCase.texts {
+			score, ok := fuzzyScore(testCase.pattern, text)
+			if !ok {
+				t.Errorf("'%s' does not match '%s'.", testCase.pattern, text)
+			} else if i > 0 && score >= previousScore {
+				t.Errorf("'%s' matches '%s' with %d, expected less than %d.",
+					testCase.pattern, text, score, previousScore)
+			}
+			previousScore = score
+		}
+	}
+}
+
+var fuzzyMismatchTestCases = []struct {
+	pattern string
+	text    string
+}{
+	{"acb", "abc"},
+	{"abcd", "abc"},
+	{"x", ""},
+}
+
+func TestFuzzyScoreMismatch(t *testing.T) {
+	for _, testCase := range fuzzyMismatchTestCases {
+		if _, ok := fuzzyScore(testCase.pattern, testCase.text); ok {
+			t.Errorf("'%s' matches '%s'.", testCase.pattern, testCase.text)
+		}
+	}
+	if score, ok := fuzzyScore("", "abc"); !ok || score != 0 {
+		t.Errorf("Got %d, %t for an empty pattern, expected 0, true.", score, ok)
+	}
+}
+
+var pickerDoc = "=> /0 Apple pie\n" +
+	"text\n" +
+	"=> /1 Pineapple\n" +
+	"=> /2 Banana\n" +
+	"=> /3 Pie\n"
+
+var linkPickerTestCases = []struct {
+	filter  string
+	indexes []int // Original indexes of the picked links.
+}{
+	{"", []int{0, 1, 2, 3}},
+	{"pie", []int{3, 1, 0}},
+	{"/2", []int{2}},
+	{"xyz", []int{}},
+}
+
+func TestLinkPickerView(t *testing.T) {
+	v, err := NewView(strings.NewReader(pickerDoc), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, testCase := range linkPickerTestCases {
+		picker := v.LinkPickerView(testCase.filter)
+		if len(picker.lines) != len(testCase.indexes) {
+			t.Errorf("Got %d links for '%s', expected %d.",
+				len(picker.lines), testCase.filter, len(testCase.indexes))
+			continue
+		}
+		for i, expected := range testCase.indexes {
+			if got := picker.OriginalIndex(i); got != expected {
+				t.Errorf("Got original index %d for link %d of '%s', expected %d.",
+					got, i, testCase.filter, expected)
+			}
+			if picker.NthLink(i).URL() != v.NthLink(expected).URL() {
+				t.Errorf("Got '%s' for link %d of '%s', expected '%s'.",
+					picker.NthLink(i).URL(), i, testCase.filter, v.NthLink(expected).URL())
+			}
+		}
+		focus, ok := picker.Focus()
+		if len(testCase.indexes) > 0 && (!ok || focus != 0) {
+			t.Errorf("The best match for '%s' is not focused.", testCase.filter)
+		} else if len(testCase.indexes) == 0 && ok {
+			t.Errorf("Got a focus without matches for '%s'.", testCase.filter)
+		}
+	}
+	if got := v.OriginalIndex(2); got != 2 {
+		t.Errorf("Got original index %d outside a link picker, expected 2.", got)
+	}
+}
@@ -0,0 +1,108 @@
+package readline
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
+)
+
+// Draw draws prompt, followed by the line, into row y of screen,
+// starting at column x and using at most width columns. If the line is
+// too wide, it is scrolled horizontally, so that the cursor stays
+// visible, and the cut off parts are replaced by "…".
+func (e *Editor) Draw(screen tcell.Screen, x, y, width int, style tcell.Style, prompt string) {
+	DrawLine(screen, x, y, width, style, prompt, e.line, e.cursor)
+}
+
+// DrawLine draws prompt and text like Editor.Draw, with the cursor at
+// the byte index cursor of text. The cursor is drawn by reversing style.
+func DrawLine(screen tcell.Screen, x, y, width int, style tcell.Style, prompt, text string, cursor int) {
+	if width < 5 {
+		return
+	}
+	textWidth := runewidth.StringWidth(text)
+	out := prompt
+	width -= runewidth.StringWidth(prompt)
+	outCursor := len(out) // Byte index of cursor within out.
+	if textWidth < width || cursor < len(text) && textWidth == width {
+		// Text fits within width.
+		out += text
+		outCursor += cursor
+	} else if cursor == 0 {
+		// Start at cursor.
+		width -= 1
+		endIndex := headOfText(text[cursor:], width)
+		out += text[cursor:cursor+endIndex] + "…"
+	} else {
+		out += "…"
+		outCursor = len(out)
+		width -= 1
+		if cursor == len(text) {
+			// Cursor is behind last character of text.
+			width -= 1
+			startIndex := tailOfText(text, textWidth, width)
+			out += text[startIndex:]
+			outCursor = len(out)
+		} else if runewidth.StringWidth(text[cursor:]) < width {
+			// Start before cursor.
+			startIndex := tailOfText(text, textWidth, width)
+			out += text[startIndex:]
+			outCursor += cursor - startIndex
+		} else {
+			// Start at cursor.
+			width -= 1
+			endIndex := headOfText(text[cursor:], width)
+			out += text[cursor:cursor+endIndex] + "…"
+		}
+	}
+	emitStrWithCursor(screen, x, y, style, out, outCursor)
+}
+
+// tailOfText returns the index within text, where the tail, that fits
+// within maxWidth, begins.
+func tailOfText(text string, textWidth, maxWidth int) (startIndex int) {
+	seenWidth := 0
+	stopOnNextRealRune := false
+	for i, r := range text {
+		rw := runewidth.RuneWidth(r)
+		seenWidth += rw
+		if stopOnNextRealRune && rw > 0 {
+			startIndex = i
+			break
+		}
+		if textWidth-seenWidth <= maxWidth {
+			stopOnNextRealRune = true
+		}
+	}
+	return startIndex
+}
+
+// headOfText returns the index within text, where the head, that fits
+// within maxWidth, ends.
+func headOfText(text string, maxWidth int) (endIndex int) {
+	seenWidth := 0
+	for i, r := range text {
+		seenWidth += runewidth.RuneWidth(r)
+		endIndex = i
+		if seenWidth > maxWidth {
+			break
+		}
+	}
+	return endIndex
+}
+
+func emitStrWithCursor(s tcell.Screen, x, y int, style tcell.Style, str string, cursor int) {
+	if cursor == len(str) {
+		str += " "
+	}
+	for i, c := range str {
+		var comb []rune
+		w := runewidth.RuneWidth(c)
+		if w == 0 {
+			comb = []rune{c}
+			c = ' '
+			w = 1
+		}
+		s.SetContent(x, y, c, comb, style.Reverse(i != cursor))
+		x += w
+	}
+}
@@ -0,0 +1,113 @@
+package readline
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+)
+
+// clusterBounds returns the byte indexes in s, at which grapheme
+// clusters start, followed by len(s).
+func clusterBounds(s string) []int {
+	bounds := []int{0}
+	state := -1
+	for i := 0; i < len(s); {
+		var cluster string
+		cluster, _, _, state = uniseg.FirstGraphemeClusterInString(s[i:], state)
+		i += len(cluster)
+		bounds = append(bounds, i)
+	}
+	return bounds
+}
+
+// prevBound returns the start of the grapheme cluster before cursor.
+func (e *Editor) prevBound() int {
+	prev := 0
+	for _, b := range clusterBounds(e.line) {
+		if b >= e.cursor {
+			break
+		}
+		prev = b
+	}
+	return prev
+}
+
+// nextBound returns the end of the grapheme cluster under cursor.
+func (e *Editor) nextBound() int {
+	for _, b := range clusterBounds(e.line) {
+		if b > e.cursor {
+			return b
+		}
+	}
+	return len(e.line)
+}
+
+// isWordCluster reports whether the grapheme cluster, that starts at
+// index i of line, is part of a word. Words are made of letters,
+// digits and underscores.
+func (e *Editor) isWordCluster(i int) bool {
+	r, _ := utf8.DecodeRuneInString(e.line[i:])
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// prevWordStart returns the start of the word before cursor, like
+// Alt-B in emacs.
+func (e *Editor) prevWordStart() int {
+	bounds := clusterBounds(e.line)
+	i := len(bounds) - 1
+	for i > 0 && bounds[i] > e.cursor {
+		i--
+	}
+	for i > 0 && !e.isWordCluster(bounds[i-1]) {
+		i--
+	}
+	for i > 0 && e.isWordCluster(bounds[i-1]) {
+		i--
+	}
+	return bounds[i]
+}
+
+// nextWordEnd returns the end of the word after cursor, like Alt-F in
+// emacs.
+func (e *Editor) nextWordEnd() int {
+	bounds := clusterBounds(e.line)
+	i := 0
+	for i < len(bounds)-1 && bounds[i] < e.cursor {
+		i++
+	}
+	for i < len(bounds)-1 && !e.isWordCluster(bounds[i]) {
+		i++
+	}
+	for i < len(bounds)-1 && e.isWordCluster(bounds[i]) {
+		i++
+	}
+	return bounds[i]
+}
+
+func (e *Editor) goLeft() {
+	e.cursor = e.prevBound()
+}
+
+func (e *Editor) goRight() {
+	e.cursor = e.nextBound()
+}
+
+func (e *Editor) insertRune(input rune) {
+	e.insertString(string(input))
+}
+
+func (e *Editor) insertString(s string) {
+	e.line = e.line[:e.cursor] + s + e.line[e.cursor:]
+	e.cursor += len(s)
+}
+
+func (e *Editor) deleteCharUnderCursor() {
+	e.line = e.line[:e.cursor] + e.line[e.nextBound():]
+}
+
+func (e *Editor) deleteCharBeforeCursor() {
+	end := e.cursor
+	e.cursor = e.prevBound()
+	e.line = e.line[:e.cursor] + e.line[end:]
+}
@@ -0,0 +1,70 @@
+package readline
+
+// MaxHistory is the maximum number of entries kept in the history.
+const MaxHistory = 1000
+
+// SetHistory replaces the history with entries, which are ordered from
+// oldest to newest. Duplicates are removed, keeping the newest, and
+// only the newest MaxHistory entries are kept.
+func (e *Editor) SetHistory(entries []string) {
+	e.history = make([]string, 0, len(entries))
+	for _, entry := range entries {
+		e.addToHistory(entry)
+	}
+}
+
+// addToHistory appends entry to the history and removes an older equal
+// entry.
+func (e *Editor) addToHistory(entry string) {
+	for i, h := range e.history {
+		if h == entry {
+			e.history = append(e.history[:i], e.history[i+1:]...)
+			break
+		}
+	}
+	e.history = append(e.history, entry)
+	if len(e.history) > MaxHistory {
+		e.history = e.history[len(e.history)-MaxHistory:]
+	}
+	e.historyIndex = len(e.history) - 1
+	e.browsing = false
+}
+
+// historyPrev displays the previous history entry, that starts with the
+// line typed before browsing.
+func (e *Editor) historyPrev() {
+	if !e.browsing {
+		e.browsing = true
+		e.browseIndex = len(e.history)
+		e.browsePrefix = e.line
+	}
+	for i := e.browseIndex - 1; i >= 0; i-- {
+		if hasPrefix(e.history[i], e.browsePrefix) {
+			e.browseIndex = i
+			e.SetInput(e.history[i])
+			return
+		}
+	}
+}
+
+// historyNext displays the next history entry, that starts with the line
+// typed before browsing. After the newest entry, the typed line is
+// displayed again.
+func (e *Editor) historyNext() {
+	if !e.browsing {
+		return
+	}
+	for i := e.browseIndex + 1; i < len(e.history); i++ {
+		if hasPrefix(e.history[i], e.browsePrefix) {
+			e.browseIndex = i
+			e.SetInput(e.history[i])
+			return
+		}
+	}
+	e.browsing = false
+	e.SetInput(e.browsePrefix)
+}
+
+func hasPrefix(s, prefix string) bool {
+	return len(s) >= len(prefix) && s[:len(prefix)] == prefix
+}
@@ -0,0 +1,62 @@
+package readline
+
+// killRingSize is the maximum number of entries kept in the kill ring.
+const killRingSize = 16
+
+type action int
+
+const (
+	otherAction = action(iota)
+	killAction
+	yankAction
+)
+
+// The killed texts, from oldest to newest. The kill ring is shared by
+// all editors, so that text can be moved between them.
+var killRing []string
+
+// kill removes line[from:to] and stores it in the kill ring.
+func (e *Editor) kill(from, to int) {
+	if from == to {
+		return
+	}
+	text := e.line[from:to]
+	switch {
+	case e.prevAction == killAction && len(killRing) > 0 && from < e.cursor:
+		killRing[len(killRing)-1] = text + killRing[len(killRing)-1]
+	case e.prevAction == killAction && len(killRing) > 0:
+		killRing[len(killRing)-1] += text
+	default:
+		killRing = append(killRing, text)
+		if len(killRing) > killRingSize {
+			killRing = killRing[len(killRing)-killRingSize:]
+		}
+	}
+	e.line = e.line[:from] + e.line[to:]
+	e.cursor = from
+	e.lastAction = killAction
+}
+
+// yank inserts the newest entry of the kill ring at the cursor.
+func (e *Editor) yank() {
+	if len(killRing) == 0 {
+		return
+	}
+	e.yankIndex = len(killRing) - 1
+	e.yankStart = e.cursor
+	e.insertString(killRing[e.yankIndex])
+	e.lastAction = yankAction
+}
+
+// yankPop replaces the text, that was just yanked, with the previous
+// entry of the kill ring.
+func (e *Editor) yankPop() {
+	if e.prevAction != yankAction || len(killRing) == 0 {
+		return
+	}
+	e.line = e.line[:e.yankStart] + e.line[e.cursor:]
+	e.cursor = e.yankStart
+	e.yankIndex = (e.yankIndex + len(killRing) - 1) % len(killRing)
+	e.insertString(killRing[e.yankIndex])
+	e.lastAction = yankAction
+}
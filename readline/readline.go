@@ -1,162 +1,187 @@
 package readline
 
 import (
-	"unicode/utf8"
-
 	"github.com/gdamore/tcell/v2"
-	"github.com/mattn/go-runewidth"
 )
 
-var (
+type Status int
+
+const (
+	Reading = Status(iota)
+	Done
+	Aborted
+)
+
+// An Editor edits a single line of text and keeps a history of the
+// entered lines. The zero value is an empty editor with an empty
+// history.
+type Editor struct {
 	line string
 
 	// Byte-Index of line on which the cursor is located. Will always
-	// be at the beginning of a unicode character or len(line).
+	// be at the beginning of a grapheme cluster or len(line).
 	cursor int
 
 	history      []string
 	historyIndex int
-)
 
-type Status int
+	// True, while entries of the history are being browsed.
+	browsing bool
 
-const (
-	Reading = Status(iota)
-	Done
-	Aborted
-)
+	// The index in history of the entry, that is displayed while
+	// browsing. It is len(history), if the line, that was being typed
+	// before browsing, is displayed.
+	browseIndex int
+
+	// The line, that was being typed before browsing. Only entries,
+	// that start with it, are browsed.
+	browsePrefix string
+
+	// The index in killRing of the entry, that was yanked last.
+	yankIndex int
+
+	// The start of the text, that was yanked last. It ends at cursor.
+	yankStart int
+
+	// The kind of the last processed key and the one before it.
+	// Consecutive kills are joined into one entry of the kill ring and
+	// Alt-Y only works directly after a yank.
+	lastAction, prevAction action
+}
 
-func Input() string {
-	return line
+func (e *Editor) Input() string {
+	return e.line
 }
 
-func Cursor() int {
-	return cursor
+func (e *Editor) Cursor() int {
+	return e.cursor
 }
 
-func History() ([]string, int) {
-	return history, historyIndex
+func (e *Editor) History() ([]string, int) {
+	return e.history, e.historyIndex
+}
+
+// SetInput replaces the current line with s and puts the cursor at its
+// end.
+func (e *Editor) SetInput(s string) {
+	e.line = s
+	e.cursor = len(s)
+}
+
+// Clear discards the current line without adding it to the history.
+func (e *Editor) Clear() {
+	e.line = ""
+	e.cursor = 0
+	e.browsing = false
+	e.lastAction = otherAction
 }
 
 // ProcessKey processes a single key input. If the key changed the
 // status to Done or Aborted, the current line will be cleared and
 // either added to the history or discarded.
-func ProcessKey(ev *tcell.EventKey) Status {
-	// TODO: Implement history entry selection.
-	/*
-		TODO: Implement all these shortcuts from https://github.com/peterh/liner:
-
-		Ctrl-A, Home         : Move cursor to beginning of line
-		Ctrl-E, End          : Move cursor to end of line
-		Ctrl-B, Left         : Move cursor one character left
-		Ctrl-F, Right        : Move cursor one character right
-		Ctrl-Left, Alt-B     : Move cursor to previous word
-		Ctrl-Right, Alt-F    : Move cursor to next word
-		Ctrl-D, Del          : (if line is not empty) Delete character under cursor
-		Ctrl-C, Esc          : Abort
-		Ctrl-H, BackSpace    : Delete character before cursor
-		Ctrl-W, Alt-BackSpace: Delete word leading up to cursor
-		Alt-D                : Delete word following cursor
-		Ctrl-K               : Delete from cursor to end of line
-		Ctrl-U               : Delete from start of line to cursor
-		Ctrl-P, Up           : Previous match from history
-		Ctrl-N, Down         : Next match from history
-	*/
+//
+// The keys work like in the shortcuts of emacs:
+//
+//	Ctrl-A, Home         : Move cursor to beginning of line
+//	Ctrl-E, End          : Move cursor to end of line
+//	Ctrl-B, Left         : Move cursor one character left
+//	Ctrl-F, Right        : Move cursor one character right
+//	Ctrl-Left, Alt-B     : Move cursor to previous word
+//	Ctrl-Right, Alt-F    : Move cursor to next word
+//	Ctrl-D, Del          : Delete character under cursor
+//	Ctrl-C, Esc          : Abort
+//	Ctrl-H, BackSpace    : Delete character before cursor
+//	Ctrl-W, Alt-BackSpace: Delete word leading up to cursor
+//	Alt-D                : Delete word following cursor
+//	Ctrl-K               : Delete from cursor to end of line
+//	Ctrl-U               : Delete from start of line to cursor
+//	Ctrl-Y               : Insert the last deleted text
+//	Alt-Y                : Replace inserted text with the one deleted before
+//	Up, Ctrl-P           : Show previous history entry
+//	Down, Ctrl-N         : Show next history entry
+//
+// Texts deleted by Ctrl-W, Alt-BackSpace, Alt-D, Ctrl-K and Ctrl-U are
+// stored in the kill ring, where consecutive deletions are joined.
+func (e *Editor) ProcessKey(ev *tcell.EventKey) Status {
+	e.prevAction, e.lastAction = e.lastAction, otherAction
 	switch ev.Key() {
-	case tcell.KeyLeft:
-		goLeft()
+	case tcell.KeyUp, tcell.KeyCtrlP:
+		e.historyPrev()
 		return Reading
-	case tcell.KeyRight:
-		goRight()
+	case tcell.KeyDown, tcell.KeyCtrlN:
+		e.historyNext()
 		return Reading
+	}
+	e.browsing = false
+	alt := ev.Modifiers()&tcell.ModAlt != 0
+	ctrl := ev.Modifiers()&tcell.ModCtrl != 0
+	switch ev.Key() {
+	case tcell.KeyHome, tcell.KeyCtrlA:
+		e.cursor = 0
+	case tcell.KeyEnd, tcell.KeyCtrlE:
+		e.cursor = len(e.line)
+	case tcell.KeyLeft:
+		if ctrl || alt {
+			e.cursor = e.prevWordStart()
+		} else {
+			e.goLeft()
+		}
+	case tcell.KeyRight:
+		if ctrl || alt {
+			e.cursor = e.nextWordEnd()
+		} else {
+			e.goRight()
+		}
+	case tcell.KeyCtrlB:
+		e.goLeft()
+	case tcell.KeyCtrlF:
+		e.goRight()
 	case tcell.KeyBackspace, tcell.KeyBackspace2:
-		if cursor > 0 {
-			goLeft()
-			deleteCharUnderCursor()
+		if alt {
+			e.kill(e.prevWordStart(), e.cursor)
+		} else {
+			e.deleteCharBeforeCursor()
 		}
-		return Reading
-	case tcell.KeyDelete:
-		deleteCharUnderCursor()
-		return Reading
+	case tcell.KeyDelete, tcell.KeyCtrlD:
+		e.deleteCharUnderCursor()
+	case tcell.KeyCtrlW:
+		e.kill(e.prevWordStart(), e.cursor)
+	case tcell.KeyCtrlK:
+		e.kill(e.cursor, len(e.line))
+	case tcell.KeyCtrlU:
+		e.kill(0, e.cursor)
+	case tcell.KeyCtrlY:
+		e.yank()
 	case tcell.KeyEnter:
-		if line == "" {
+		if e.line == "" {
 			return Aborted
 		}
-		history = append(history, line)
-		historyIndex = len(history) - 1
-		line = ""
-		cursor = 0
+		e.addToHistory(e.line)
+		e.Clear()
 		return Done
 	case tcell.KeyEsc, tcell.KeyCtrlC:
-		line = ""
-		cursor = 0
+		e.Clear()
 		return Aborted
 	case tcell.KeyRune:
-		insertRune(ev.Rune())
-		return Reading
-	}
-	return Reading
-}
-
-func goLeft() {
-	if cursor == 0 {
-		return
-	}
-	var prevRuneIndex int
-	for i := range line {
-		if i >= cursor {
-			break
+		if alt {
+			e.processAltRune(ev.Rune())
+		} else {
+			e.insertRune(ev.Rune())
 		}
-		prevRuneIndex = i
 	}
-	cursor = prevRuneIndex
-}
-
-func goRight() {
-	if cursor == len(line) {
-		return
-	}
-	var nextRuneIndex int
-	for i := range line {
-		nextRuneIndex = i
-		if i > cursor {
-			break
-		}
-	}
-	if nextRuneIndex == cursor {
-		cursor = len(line)
-	} else {
-		cursor = nextRuneIndex
-	}
-}
-
-func insertRune(input rune) {
-	if cursor == len(line) {
-		line += string(input)
-	} else {
-		line = line[:cursor] + string(input) + line[cursor:]
-	}
-	cursor += utf8.RuneLen(input)
+	return Reading
 }
 
-func deleteCharUnderCursor() {
-	if cursor == len(line) {
-		return
-	}
-	endOfDelete := cursor
-	for i, r := range line[cursor:] {
-		if i == 0 {
-			continue
-		}
-		rw := runewidth.RuneWidth(r)
-		if rw > 0 {
-			endOfDelete = cursor + i
-			break
-		}
-	}
-	if endOfDelete == cursor {
-		line = line[:cursor]
-	} else {
-		line = line[:cursor] + line[endOfDelete:]
+// processAltRune processes a rune, that was typed while holding Alt.
+func (e *Editor) processAltRune(r rune) {
+	switch r {
+	case 'b', 'B':
+		e.cursor = e.prevWordStart()
+	case 'f', 'F':
+		e.cursor = e.nextWordEnd()
+	case 'd', 'D':
+		e.kill(e.cursor, e.nextWordEnd())
+	case 'y', 'Y':
+		e.yankPop()
 	}
 }
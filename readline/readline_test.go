@@ -0,0 +1,222 @@
+package readline_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/codesoap/gmir/readline"
+	"github.com/gdamore/tcell/v2"
+)
+
+func key(k tcell.Key) *tcell.EventKey {
+	return tcell.NewEventKey(k, 0, tcell.ModNone)
+}
+
+func ctrl(k tcell.Key) *tcell.EventKey {
+	return tcell.NewEventKey(k, 0, tcell.ModCtrl)
+}
+
+func alt(r rune) *tcell.EventKey {
+	return tcell.NewEventKey(tcell.KeyRune, r, tcell.ModAlt)
+}
+
+// editingTestCases type a text and then press keys. The expected line
+// marks the cursor with '|'.
+var editingTestCases = []struct {
+	name     string
+	text     string
+	keys     []*tcell.EventKey
+	expected string
+}{
+	{"typing", "foo bar", nil, "foo bar|"},
+	{"Ctrl-A", "foo bar", []*tcell.EventKey{key(tcell.KeyCtrlA)}, "|foo bar"},
+	{"Home and End", "foo", []*tcell.EventKey{key(tcell.KeyHome), key(tcell.KeyEnd)}, "foo|"},
+	{"Ctrl-B", "foo", []*tcell.EventKey{key(tcell.KeyCtrlB)}, "fo|o"},
+	{"Ctrl-F", "foo", []*tcell.EventKey{key(tcell.KeyCtrlA), key(tcell.KeyCtrlF)}, "f|oo"},
+	{"Left over combining mark", "café", []*tcell.EventKey{key(tcell.KeyLeft)}, "caf|é"},
+	{"Left over emoji sequence", "a👍🏽", []*tcell.EventKey{key(tcell.KeyLeft)}, "a|👍🏽"},
+	{"Alt-B", "foo bar.baz", []*tcell.EventKey{alt('b'), alt('b')}, "foo |bar.baz"},
+	{"Ctrl-Left", "foo  bar ", []*tcell.EventKey{ctrl(tcell.KeyLeft)}, "foo  |bar "},
+	{"Alt-F", "foo bar", []*tcell.EventKey{key(tcell.KeyHome), alt('f')}, "foo| bar"},
+	{"Ctrl-Right", "foo bar", []*tcell.EventKey{key(tcell.KeyHome), ctrl(tcell.KeyRight), ctrl(tcell.KeyRight)}, "foo bar|"},
+	{"word with accents", "naïve café", []*tcell.EventKey{alt('b')}, "naïve |café"},
+	{"Ctrl-D", "foo", []*tcell.EventKey{key(tcell.KeyHome), key(tcell.KeyCtrlD)}, "|oo"},
+	{"Del at end", "foo", []*tcell.EventKey{key(tcell.KeyDelete)}, "foo|"},
+	{"Del combining mark", "éx", []*tcell.EventKey{key(tcell.KeyHome), key(tcell.KeyDelete)}, "|x"},
+	{"Backspace", "foo", []*tcell.EventKey{key(tcell.KeyBackspace2)}, "fo|"},
+	{"Ctrl-H", "foo", []*tcell.EventKey{key(tcell.KeyCtrlH)}, "fo|"},
+	{"Backspace at start", "foo", []*tcell.EventKey{key(tcell.KeyHome), key(tcell.KeyBackspace2)}, "|foo"},
+	{"Ctrl-W", "foo bar", []*tcell.EventKey{key(tcell.KeyCtrlW)}, "foo |"},
+	{"Alt-Backspace", "a(b.c)", []*tcell.EventKey{tcell.NewEventKey(tcell.KeyBackspace2, 0, tcell.ModAlt)}, "a(b.|"},
+	{"Alt-D", "foo bar baz", []*tcell.EventKey{key(tcell.KeyHome), alt('d')}, "| bar baz"},
+	{"Ctrl-K", "foo bar", []*tcell.EventKey{alt('b'), key(tcell.KeyCtrlK)}, "foo |"},
+	{"Ctrl-U", "foo bar", []*tcell.EventKey{alt('b'), key(tcell.KeyCtrlU)}, "|bar"},
+	{"Ctrl-Y", "foo bar", []*tcell.EventKey{key(tcell.KeyCtrlW), key(tcell.KeyHome), key(tcell.KeyCtrlY)}, "bar|foo "},
+	{"Ctrl-Y joins kills", "foo bar baz", []*tcell.EventKey{key(tcell.KeyCtrlW), key(tcell.KeyCtrlW), key(tcell.KeyCtrlY), key(tcell.KeyCtrlY)}, "foo bar bazbar baz|"},
+	{"Alt-Y", "foo bar", []*tcell.EventKey{key(tcell.KeyCtrlW), key(tcell.KeyHome), key(tcell.KeyCtrlK), key(tcell.KeyCtrlY), alt('y')}, "bar|"},
+	{"Alt-Y without yank", "foo", []*tcell.EventKey{key(tcell.KeyCtrlW), key(tcell.KeyCtrlY), key(tcell.KeyLeft), alt('y')}, "fo|o"},
+	{"Alt rune is not inserted", "foo", []*tcell.EventKey{alt('x')}, "foo|"},
+}
+
+func TestEditing(t *testing.T) {
+	for _, testCase := range editingTestCases {
+		t.Logf("Testing %s.", testCase.name)
+		var e readline.Editor
+		for _, r := range testCase.text {
+			e.ProcessKey(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+		}
+		for _, ev := range testCase.keys {
+			if status := e.ProcessKey(ev); status != readline.Reading {
+				t.Fatalf("Got status %d, expected Reading.", status)
+			}
+		}
+		input, cursor := e.Input(), e.Cursor()
+		if got := input[:cursor] + "|" + input[cursor:]; got != testCase.expected {
+			t.Errorf("Got '%s', expected '%s'.", got, testCase.expected)
+		}
+	}
+}
+
+func TestEnter(t *testing.T) {
+	var e readline.Editor
+	for _, r := range "foo" {
+		e.ProcessKey(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+	}
+	if status := e.ProcessKey(key(tcell.KeyEnter)); status != readline.Done {
+		t.Errorf("Got status %d, expected Done.", status)
+	}
+	if history, _ := e.History(); len(history) == 0 || history[len(history)-1] != "foo" {
+		t.Errorf("Line was not added to the history.")
+	}
+	if status := e.ProcessKey(key(tcell.KeyEnter)); status != readline.Aborted {
+		t.Errorf("Got status %d for an empty line, expected Aborted.", status)
+	}
+}
+
+func TestSeparateHistories(t *testing.T) {
+	var a, b readline.Editor
+	a.SetHistory([]string{"foo"})
+	if history, _ := b.History(); len(history) != 0 {
+		t.Errorf("History of one editor was added to another.")
+	}
+	b.ProcessKey(key(tcell.KeyUp))
+	if b.Input() != "" {
+		t.Errorf("Got '%s' from an empty history.", b.Input())
+	}
+}
+
+// historyTestCases type a text into an editor with the history
+// "foo", "bar", "foobar", "baz" and then press keys.
+var historyTestCases = []struct {
+	name     string
+	text     string
+	keys     []*tcell.EventKey
+	expected string
+}{
+	{"Up", "", []*tcell.EventKey{key(tcell.KeyUp)}, "baz"},
+	{"Up twice", "", []*tcell.EventKey{key(tcell.KeyUp), key(tcell.KeyUp)}, "foobar"},
+	{"Up past oldest", "", []*tcell.EventKey{key(tcell.KeyUp), key(tcell.KeyUp), key(tcell.KeyUp), key(tcell.KeyUp), key(tcell.KeyUp)}, "foo"},
+	{"Ctrl-P", "", []*tcell.EventKey{key(tcell.KeyCtrlP), key(tcell.KeyCtrlP)}, "foobar"},
+	{"Down", "", []*tcell.EventKey{key(tcell.KeyUp), key(tcell.KeyUp), key(tcell.KeyDown)}, "baz"},
+	{"Ctrl-N", "", []*tcell.EventKey{key(tcell.KeyCtrlP), key(tcell.KeyCtrlP), key(tcell.KeyCtrlN)}, "baz"},
+	{"Down without browsing", "qux", []*tcell.EventKey{key(tcell.KeyDown)}, "qux"},
+	{"Down past newest", "qux", []*tcell.EventKey{key(tcell.KeyUp), key(tcell.KeyDown)}, "qux"},
+	{"prefix", "fo", []*tcell.EventKey{key(tcell.KeyUp)}, "foobar"},
+	{"prefix Up twice", "fo", []*tcell.EventKey{key(tcell.KeyUp), key(tcell.KeyUp)}, "foo"},
+	{"prefix Down", "fo", []*tcell.EventKey{key(tcell.KeyUp), key(tcell.KeyUp), key(tcell.KeyCtrlN)}, "foobar"},
+	{"prefix Down past newest", "fo", []*tcell.EventKey{key(tcell.KeyUp), key(tcell.KeyDown)}, "fo"},
+	{"prefix without match", "qux", []*tcell.EventKey{key(tcell.KeyUp)}, "qux"},
+}
+
+func TestHistoryBrowsing(t *testing.T) {
+	for _, testCase := range historyTestCases {
+		t.Logf("Testing %s.", testCase.name)
+		var e readline.Editor
+		e.SetHistory([]string{"foo", "bar", "foobar", "baz"})
+		for _, r := range testCase.text {
+			e.ProcessKey(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+		}
+		for _, ev := range testCase.keys {
+			e.ProcessKey(ev)
+		}
+		if got := e.Input(); got != testCase.expected {
+			t.Errorf("Got '%s', expected '%s'.", got, testCase.expected)
+		}
+	}
+}
+
+func TestHistoryDeduplication(t *testing.T) {
+	var e readline.Editor
+	e.SetHistory([]string{"foo", "bar", "foo"})
+	for _, r := range "bar" {
+		e.ProcessKey(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+	}
+	e.ProcessKey(key(tcell.KeyEnter))
+	history, _ := e.History()
+	if len(history) != 2 || history[0] != "foo" || history[1] != "bar" {
+		t.Errorf("Got history %q, expected [\"foo\" \"bar\"].", history)
+	}
+	e.ProcessKey(key(tcell.KeyUp))
+	e.ProcessKey(key(tcell.KeyUp))
+	e.ProcessKey(key(tcell.KeyUp))
+	if e.Input() != "foo" {
+		t.Errorf("Got '%s' for the oldest entry, expected 'foo'.", e.Input())
+	}
+}
+
+func TestHistoryCap(t *testing.T) {
+	entries := make([]string, readline.MaxHistory+10)
+	for i := range entries {
+		entries[i] = strconv.Itoa(i)
+	}
+	var e readline.Editor
+	e.SetHistory(entries)
+	history, _ := e.History()
+	if len(history) != readline.MaxHistory {
+		t.Fatalf("Got %d entries, expected %d.", len(history), readline.MaxHistory)
+	}
+	if history[0] != "10" {
+		t.Errorf("Got '%s' as oldest entry, expected '10'.", history[0])
+	}
+	for _, r := range "new" {
+		e.ProcessKey(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+	}
+	e.ProcessKey(key(tcell.KeyEnter))
+	history, _ = e.History()
+	if len(history) != readline.MaxHistory || history[0] != "11" || history[len(history)-1] != "new" {
+		t.Errorf("The history was not capped after adding an entry.")
+	}
+}
+
+var drawTestCases = []struct {
+	text     string
+	cursor   int
+	expected string
+}{
+	{"foo", 3, "/foo      "},
+	{"foo bar baz", 11, "/…ar baz  "},
+	{"foo bar baz", 0, "/foo bar… "},
+	{"foo bar baz", 4, "/…bar ba… "},
+	{"foo bar baz", 8, "/…bar baz "},
+}
+
+func TestDrawLine(t *testing.T) {
+	s := tcell.NewSimulationScreen("")
+	if err := s.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Fini()
+	s.SetSize(10, 1)
+	for _, testCase := range drawTestCases {
+		s.Clear()
+		readline.DrawLine(s, 0, 0, 9, tcell.StyleDefault, "/", testCase.text, testCase.cursor)
+		got := ""
+		for x := 0; x < 10; x++ {
+			r, _, _, _ := s.GetContent(x, 0)
+			got += string(r)
+		}
+		if got != testCase.expected {
+			t.Errorf("Got '%s' for cursor %d in '%s', expected '%s'.", got, testCase.cursor, testCase.text, testCase.expected)
+		}
+	}
+}
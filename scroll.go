@@ -2,16 +2,18 @@ package gmir
 
 import (
 	"math"
+	"strings"
 
 	"github.com/codesoap/gmir/parser"
 	"github.com/gdamore/tcell/v2"
 )
 
 // Scroll scrolls up or down the given amount of (wrapped) lines. Scrolls
-// up, if lines is negative. Never scrolls past the top or bottom line.
+// up, if lines is positive, and down, if it is negative. Never scrolls
+// past the top or bottom line.
 func (v *View) Scroll(screen tcell.Screen, lines int) {
 	// TODO: Optimize, so that the same line is not wrapped multiple times.
-	if lines == 0 {
+	if lines == 0 || v.IsEmpty() {
 		return
 	}
 	for lines > 0 && (v.line > 0 || v.lineOffset > 0) {
@@ -36,25 +38,58 @@ func (v *View) Scroll(screen tcell.Screen, lines int) {
 	// TODO: Maybe ensure the last line will not scroll over the bottom of screen.
 }
 
+// Position returns the index of the first displayed line and the number
+// of wrapped lines skipped within it.
+func (v View) Position() (line, lineOffset int) {
+	return v.line, v.lineOffset
+}
+
+// ScrollToPosition scrolls to a position, as returned by Position. The
+// position is limited to the lines available in v.
+func (v *View) ScrollToPosition(screen tcell.Screen, line, lineOffset int) {
+	if v.IsEmpty() {
+		return
+	}
+	if line >= len(v.lines) {
+		line, lineOffset = len(v.lines)-1, math.MaxInt
+	}
+	if line < 0 {
+		line, lineOffset = 0, 0
+	}
+	v.line = line
+	v.lineOffset = lineOffset
+	if maxLineOffset := v.maxLineOffset(screen, line); v.lineOffset > maxLineOffset {
+		v.lineOffset = maxLineOffset
+	} else if v.lineOffset < 0 {
+		v.lineOffset = 0
+	}
+}
+
 // ScrollToTop scrolls to the first line.
 func (v *View) ScrollToTop(screen tcell.Screen) {
+	v.recordJump()
 	v.line = 0
 	v.lineOffset = 0
 }
 
 // ScrollToBottom scrolls to the last line.
 func (v *View) ScrollToBottom(screen tcell.Screen) {
+	if v.IsEmpty() {
+		return
+	}
+	v.recordJump()
 	v.line = len(v.lines) - 1
 	v.lineOffset = v.maxLineOffset(screen, v.line)
 }
 
 // ScrollToNextHeading scrolls to the first line of the next heading.
 func (v *View) ScrollToNextHeading(screen tcell.Screen) {
-	if v.line == len(v.lines)-1 {
+	if v.line >= len(v.lines)-1 {
 		return
 	}
 	for i, line := range v.lines[v.line+1:] {
 		if isHeading(line) {
+			v.recordJump()
 			v.line += i + 1
 			v.lineOffset = 0
 			return
@@ -69,6 +104,7 @@ func (v *View) ScrollToPrevHeading(screen tcell.Screen) {
 	}
 	for i := v.line - 1; i >= 0; i-- {
 		if isHeading(v.lines[i]) {
+			v.recordJump()
 			v.line = i
 			v.lineOffset = 0
 			return
@@ -82,6 +118,7 @@ func (v *View) ScrollToNthHeading(screen tcell.Screen, n int) {
 		if isHeading(line) {
 			n--
 			if n < 0 {
+				v.recordJump()
 				v.line = i
 				v.lineOffset = 0
 				return
@@ -90,6 +127,32 @@ func (v *View) ScrollToNthHeading(screen tcell.Screen, n int) {
 	}
 }
 
+// ScrollToLine scrolls to the line with the given number in the source,
+// starting at 1. See parser.Document.LineIndex for source lines, that
+// are not displayed.
+func (v *View) ScrollToLine(screen tcell.Screen, number int) {
+	if v.IsEmpty() {
+		return
+	}
+	v.recordJump()
+	v.ScrollToPosition(screen, v.source.LineIndex(number), 0)
+}
+
+// ScrollToHeading scrolls to the first heading, that contains text,
+// ignoring case. Returns false, if there is no such heading.
+func (v *View) ScrollToHeading(screen tcell.Screen, text string) bool {
+	text = strings.ToLower(text)
+	for i, line := range v.lines {
+		if isHeading(line) && strings.Contains(strings.ToLower(line.Text()), text) {
+			v.recordJump()
+			v.line = i
+			v.lineOffset = 0
+			return true
+		}
+	}
+	return false
+}
+
 func isHeading(line parser.Line) bool {
 	_, isHeading1 := line.(parser.Heading1Line)
 	_, isHeading2 := line.(parser.Heading2Line)
@@ -177,12 +240,14 @@ func (v *View) scrollDownToSearchMatch(screen tcell.Screen, skipFirst bool) bool
 			wrapIndexes := wrappable.WrapIndexes(textWidth)
 			for _, offset := range lineOffsetsWithMatches(wrapIndexes, matches) {
 				if i > 0 || (skipFirst && offset > v.lineOffset) || (!skipFirst && offset >= v.lineOffset) {
+					v.recordJump()
 					v.line += i
 					v.lineOffset = offset
 					return true
 				}
 			}
 		} else if (skipFirst && i > 0) || (!skipFirst && i >= 0) {
+			v.recordJump()
 			v.line += i
 			v.lineOffset = 0
 			return true
@@ -192,7 +257,7 @@ func (v *View) scrollDownToSearchMatch(screen tcell.Screen, skipFirst bool) bool
 }
 
 func (v *View) scrollUpToSearchMatch(screen tcell.Screen, skipFirst bool) bool {
-	if v.Searchpattern == nil {
+	if v.Searchpattern == nil || v.IsEmpty() {
 		return false
 	}
 	screenWidth, _ := screen.Size()
@@ -209,12 +274,14 @@ func (v *View) scrollUpToSearchMatch(screen tcell.Screen, skipFirst bool) bool {
 			for i := len(matchingLineOffsets) - 1; i >= 0; i-- {
 				offset := matchingLineOffsets[i]
 				if lineIndex < v.line || (skipFirst && offset < v.lineOffset) || (!skipFirst && offset <= v.lineOffset) {
+					v.recordJump()
 					v.line = lineIndex
 					v.lineOffset = offset
 					return true
 				}
 			}
 		} else if (skipFirst && lineIndex < v.line) || (!skipFirst && lineIndex <= v.line) {
+			v.recordJump()
 			v.line = lineIndex
 			v.lineOffset = 0
 			return true
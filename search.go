@@ -0,0 +1,165 @@
+package gmir
+
+import (
+	"regexp"
+	"unicode"
+
+	"github.com/codesoap/gmir/readline"
+	"github.com/gdamore/tcell/v2"
+)
+
+// ignoreCase makes searches case-insensitive. Otherwise searches are
+// smart-case: They are only case-sensitive, if the term contains an
+// uppercase letter.
+var ignoreCase = false
+
+// SetIgnoreCase makes all searches case-insensitive, if ignore is true.
+// Otherwise searches are smart-case.
+func SetIgnoreCase(ignore bool) {
+	ignoreCase = ignore
+}
+
+// StartSearch enters mode, which must be Search or ReverseSearch. The
+// search term is typed into prompt, which is cleared. The current
+// position is remembered, so that it can be restored by CancelSearch.
+func (v *View) StartSearch(mode Mode, prompt *readline.Editor) {
+	v.ClearSelector()
+	v.Mode = mode
+	v.searchStart = row{v.line, v.lineOffset}
+	prompt.Clear()
+	v.Prompt = prompt
+}
+
+// TakeSearch takes over the mode and search state of old, which should
+// be a previous version of the same document.
+func (v *View) TakeSearch(old View) {
+	v.Mode = old.Mode
+	v.Prompt = old.Prompt
+	v.Searchpattern = old.Searchpattern
+	v.searchStart = old.searchStart
+	v.literalSearch = old.literalSearch
+}
+
+// ToggleLiteralSearch switches between searching for regular expressions
+// and literal text.
+func (v *View) ToggleLiteralSearch() {
+	v.literalSearch = !v.literalSearch
+}
+
+// LiteralSearch returns true, if search terms are literal text instead
+// of regular expressions.
+func (v View) LiteralSearch() bool {
+	return v.literalSearch
+}
+
+// UpdateSearch searches for the term in v.Prompt while it is being
+// typed. Matches of the term are highlighted and the first match from
+// the position, where the search started, is scrolled to. Invalid terms
+// are ignored, so that the last valid one stays active.
+func (v *View) UpdateSearch(screen tcell.Screen) {
+	term := v.Prompt.Input()
+	if term == "" {
+		v.Searchpattern = nil
+		v.line, v.lineOffset = v.searchStart.line, v.searchStart.lineOffset
+		return
+	}
+	re, err := v.compileSearch(term)
+	if err != nil {
+		return
+	}
+	v.Searchpattern = re
+	v.scrollToFirstMatch(screen)
+}
+
+// FinishSearch ends typing the search term. Returns false, if term is
+// invalid or has no match. In this case, the position, where the search
+// started, is restored.
+func (v *View) FinishSearch(screen tcell.Screen, term string) bool {
+	defer func() { v.Mode = Regular }()
+	v.Prompt = nil
+	re, err := v.compileSearch(term)
+	if err != nil {
+		v.Info = "Invalid pattern"
+		v.Searchpattern = nil
+		v.line, v.lineOffset = v.searchStart.line, v.searchStart.lineOffset
+		return false
+	}
+	v.Searchpattern = re
+	if !v.scrollToFirstMatch(screen) {
+		v.Info = "Pattern not found."
+		return false
+	}
+	return true
+}
+
+// CancelSearch ends typing the search term, removes the search pattern
+// and restores the position, where the search started.
+func (v *View) CancelSearch() {
+	v.Mode = Regular
+	v.Prompt = nil
+	v.Searchpattern = nil
+	v.line, v.lineOffset = v.searchStart.line, v.searchStart.lineOffset
+}
+
+// scrollToFirstMatch scrolls to the first match of v.Searchpattern in
+// the direction of the search, starting from the position, where the
+// search started. If there is no match, the position, where the search
+// started, is restored.
+func (v *View) scrollToFirstMatch(screen tcell.Screen) bool {
+	v.line, v.lineOffset = v.searchStart.line, v.searchStart.lineOffset
+	if v.Mode == ReverseSearch {
+		return v.ScrollUpToSearchMatch(screen)
+	}
+	return v.ScrollDownToSearchMatch(screen)
+}
+
+// compileSearch compiles term according to the search options.
+func (v View) compileSearch(term string) (*regexp.Regexp, error) {
+	pattern := term
+	if v.literalSearch {
+		pattern = regexp.QuoteMeta(term)
+	}
+	if ignoreCase || !hasUpper(term) {
+		pattern = "(?i)" + pattern
+	}
+	return regexp.Compile(pattern)
+}
+
+func hasUpper(s string) bool {
+	for _, r := range s {
+		if unicode.IsUpper(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// SearchMatchCount returns the number of matches of v.Searchpattern.
+func (v View) SearchMatchCount() int {
+	if v.Searchpattern == nil {
+		return 0
+	}
+	count := 0
+	for _, line := range v.lines {
+		count += len(v.Searchpattern.FindAllStringIndex(line.Text(), -1))
+	}
+	return count
+}
+
+// searchPrompt returns the prompt, that is displayed before the search
+// term. It shows the search options and the direction of the search.
+func (v View) searchPrompt() string {
+	prompt := "[regex"
+	if v.literalSearch {
+		prompt = "[literal"
+	}
+	if ignoreCase {
+		prompt += ", ignore case]"
+	} else {
+		prompt += ", smart case]"
+	}
+	if v.Mode == ReverseSearch {
+		return prompt + "?"
+	}
+	return prompt + "/"
+}
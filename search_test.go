@@ -0,0 +1,46 @@
+package gmir
+
+import "testing"
+
+var compileSearchTestCases = []struct {
+	term       string
+	literal    bool
+	ignoreCase bool
+	text       string
+	expected   bool
+}{
+	{"foo", false, false, "FOO", true},
+	{"Foo", false, false, "foo", false},
+	{"Foo", false, false, "Foo", true},
+	{"Foo", false, true, "foo", true},
+	{"fo+", false, false, "foooo", true},
+	{"fo+", true, false, "foooo", false},
+	{"fo+", true, false, "FO+", true},
+	{"Fo+", true, false, "fo+", false},
+	{"Fo+", true, true, "fo+", true},
+	{"a.c", true, false, "abc", false},
+	{"(", true, false, "f(x)", true},
+}
+
+func TestCompileSearch(t *testing.T) {
+	defer SetIgnoreCase(false)
+	for _, testCase := range compileSearchTestCases {
+		SetIgnoreCase(testCase.ignoreCase)
+		v := View{literalSearch: testCase.literal}
+		re, err := v.compileSearch(testCase.term)
+		if err != nil {
+			t.Errorf("Could not compile '%s': %s", testCase.term, err)
+			continue
+		}
+		if got := re.MatchString(testCase.text); got != testCase.expected {
+			t.Errorf("Got %t for '%s' in '%s' (literal: %t, ignore case: %t), expected %t.",
+				got, testCase.term, testCase.text, testCase.literal, testCase.ignoreCase, testCase.expected)
+		}
+	}
+}
+
+func TestCompileInvalidSearch(t *testing.T) {
+	if _, err := (View{}).compileSearch("("); err == nil {
+		t.Errorf("Got no error for an invalid regular expression.")
+	}
+}
@@ -1,48 +1,105 @@
 package gmir
 
 import (
-	"fmt"
+	"unicode/utf8"
 
+	"github.com/codesoap/gmir/parser"
 	"github.com/codesoap/gmir/selector"
 )
 
-// AddDigitToSelector adds a single digit to the end of v.selector.
-func (v *View) AddDigitToSelector(digit int) {
-	if digit < 0 || digit > 9 {
-		panic(fmt.Sprintf("'%d' is not a digit", digit))
+// selectors is the encoding of the selectors, that are displayed next
+// to selectables.
+var selectors selector.Encoding = selector.Decimal{}
+
+// SetSelectors sets the encoding of selectors.
+func SetSelectors(e selector.Encoding) {
+	selectors = e
+}
+
+// SelectorRunes describes the runes, that selectors are made of.
+func SelectorRunes() string {
+	return selectors.String()
+}
+
+// DirectSelectors returns true, if selectors can be typed without
+// entering the Select mode first. This is only the case for decimal
+// selectors, because letters may be bound to actions.
+func DirectSelectors() bool {
+	_, isDecimal := selectors.(selector.Decimal)
+	return isDecimal
+}
+
+// AddToSelector adds r to the end of v.selector. Returns false, if r
+// cannot be part of a selector.
+func (v *View) AddToSelector(r rune) bool {
+	if !selectors.Contains(r) {
+		return false
 	}
-	v.selector += fmt.Sprint(digit)
+	v.selector += string(r)
+	return true
+}
+
+// RemoveFromSelector removes the last rune of v.selector.
+func (v *View) RemoveFromSelector() {
+	_, size := utf8.DecodeLastRuneInString(v.selector)
+	v.selector = v.selector[:len(v.selector)-size]
 }
 
-// ClearSelector sets v.selector to an empty string.
+// SelectorIsEmpty returns true, if no rune of the selector has been
+// typed yet.
+func (v View) SelectorIsEmpty() bool {
+	return v.selector == ""
+}
+
+// ClearSelector sets v.selector to an empty string and leaves the
+// Select mode.
 func (v *View) ClearSelector() {
 	v.selector = ""
+	if v.Mode == Select {
+		v.Mode = Regular
+	}
 }
 
-// SelectorNumber returns the currently selected index.
+// SelectorIndex returns the currently selected index.
 func (v View) SelectorIndex() int {
-	return selector.ToIndex(v.selector)
+	i, _ := selectors.ToIndex(v.selector, v.selectableCount())
+	return i
+}
+
+// SelectorIsComplete returns true, if no more runes can be added to the
+// selector.
+func (v View) SelectorIsComplete() bool {
+	return selectors.IsComplete(v.selector, v.selectableCount())
 }
 
 // SelectorIsValid returns true, if the selector is complete and
 // resolves to a valid selectable.
 func (v View) SelectorIsValid() bool {
-	return selector.IsComplete(v.selector) && v.selectorIsInRange()
+	_, ok := selectors.ToIndex(v.selector, v.selectableCount())
+	return ok && v.SelectorIsComplete()
 }
 
-func (v View) selectorIsInRange() bool {
-	i := selector.ToIndex(v.selector)
+func (v View) selectableCount() int {
 	switch v.selectable {
 	case link:
-		return i < len(v.links())
+		return len(v.links())
 	case heading:
-		return i < len(v.headings())
+		return len(v.headings())
 	}
 	panic("unknown selectable")
 }
 
 // LinkURL returns the URL for v.selector.
 func (v View) LinkURL() string {
-	i := selector.ToIndex(v.selector)
-	return v.links()[i].URL()
+	return v.NthLink(v.SelectorIndex()).URL()
+}
+
+// LinkCount returns the number of links in v.
+func (v View) LinkCount() int {
+	return len(v.links())
+}
+
+// NthLink returns the link with the given index.
+func (v View) NthLink(n int) parser.LinkLine {
+	return v.links()[n]
 }
@@ -4,31 +4,156 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
 )
 
-func IsComplete(number string) bool {
+// An Encoding converts between the index of a selectable and the
+// selector, that is typed to select it. The count of selectables is
+// passed along, so that encodings may choose the length of their
+// selectors according to it.
+type Encoding interface {
+	// FromIndex returns the selector for index i.
+	FromIndex(i, count int) string
+
+	// ToIndex returns the index for the complete selector s. Returns
+	// false, if s is invalid or out of range.
+	ToIndex(s string, count int) (int, bool)
+
+	// IsComplete returns true, if s needs no further runes.
+	IsComplete(s string, count int) bool
+
+	// Width returns the width of the widest selector in columns.
+	Width(count int) int
+
+	// Contains returns true, if r can be part of a selector.
+	Contains(r rune) bool
+
+	// String describes the runes of the selectors, e.g. "0-9".
+	String() string
+}
+
+// Decimal encodes indexes as decimal numbers starting at 1. Numbers with
+// n digits are preceded by n-1 zeroes, so that a selector is complete
+// as soon as its last digit is typed, regardless of count.
+type Decimal struct{}
+
+func (Decimal) FromIndex(i, count int) string {
+	digitCnt := len(strconv.Itoa(i + 1))
+	return strings.Repeat("0", digitCnt-1) + strconv.Itoa(i+1)
+}
+
+func (Decimal) ToIndex(s string, count int) (int, bool) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 || n > count {
+		return 0, false
+	}
+	return n - 1, true
+}
+
+func (Decimal) IsComplete(s string, count int) bool {
 	leadingZeroes := 0
-	for _, c := range number {
+	for _, c := range s {
 		if c != '0' {
 			break
 		}
 		leadingZeroes++
 	}
 	completeSelectorLen := leadingZeroes*2 + 1
-	// If len(number) > completeSelectorLen, the selector can never become
-	// complete. This is accepted for now.
-	return len(number) == completeSelectorLen
+	return len(s) >= completeSelectorLen
 }
 
-func FromIndex(i int) string {
-	digitCnt := len(strconv.Itoa(i + 1))
-	return strings.Repeat("0", digitCnt-1) + strconv.Itoa(i+1)
+func (d Decimal) Width(count int) int {
+	if count == 0 {
+		return 0
+	}
+	return len(d.FromIndex(count-1, count))
+}
+
+func (Decimal) Contains(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func (Decimal) String() string {
+	return "0-9"
+}
+
+// HomeRow is an Alphabet of the keys on the home row of a QWERTY
+// keyboard.
+var HomeRow = Alphabet("asdfjkl;")
+
+// An Alphabet encodes indexes as codes of its runes, like the link hints
+// of vimium. All codes have the same length, so that no code is the
+// prefix of another. Alphabets must be created with NewAlphabet.
+type Alphabet []rune
+
+// NewAlphabet returns an Alphabet of the runes of letters. There must be
+// at least two runes, each exactly one column wide and none repeated.
+func NewAlphabet(letters string) (Alphabet, error) {
+	a := Alphabet(letters)
+	if len(a) < 2 {
+		return nil, fmt.Errorf("alphabet needs at least two letters")
+	}
+	for i, r := range a {
+		if runewidth.RuneWidth(r) != 1 {
+			return nil, fmt.Errorf("letter '%c' is not one column wide", r)
+		}
+		if strings.ContainsRune(string(a[:i]), r) {
+			return nil, fmt.Errorf("letter '%c' is repeated", r)
+		}
+	}
+	return a, nil
+}
+
+// codeLen returns the length of the codes for count indexes.
+func (a Alphabet) codeLen(count int) int {
+	n := 1
+	for capacity := len(a); capacity < count; capacity *= len(a) {
+		n++
+	}
+	return n
+}
+
+func (a Alphabet) FromIndex(i, count int) string {
+	code := make([]rune, a.codeLen(count))
+	for j := len(code) - 1; j >= 0; j-- {
+		code[j] = a[i%len(a)]
+		i /= len(a)
+	}
+	return string(code)
+}
+
+func (a Alphabet) ToIndex(s string, count int) (int, bool) {
+	if !a.IsComplete(s, count) {
+		return 0, false
+	}
+	i := 0
+	for _, r := range s {
+		digit := strings.IndexRune(string(a), r)
+		if digit < 0 {
+			return 0, false
+		}
+		i = i*len(a) + utf8.RuneCountInString(string(a)[:digit])
+	}
+	return i, i < count
 }
 
-func ToIndex(number string) int {
-	n, err := strconv.Atoi(number)
-	if err != nil || n < 1 {
-		panic(fmt.Sprintf("invalid selector '%s': %v", number, err))
+func (a Alphabet) IsComplete(s string, count int) bool {
+	return utf8.RuneCountInString(s) >= a.codeLen(count)
+}
+
+func (a Alphabet) Width(count int) int {
+	if count == 0 {
+		return 0
 	}
-	return n - 1
+	return a.codeLen(count)
+}
+
+func (a Alphabet) Contains(r rune) bool {
+	return strings.ContainsRune(string(a), r)
+}
+
+func (a Alphabet) String() string {
+	return string(a)
 }
@@ -0,0 +1,47 @@
+package selector_test
+
+import (
+	"testing"
+
+	"github.com/codesoap/gmir/selector"
+)
+
+var encodingTestCases = []struct {
+	encoding selector.Encoding
+	count    int
+	index    int
+	selector string
+	width    int
+}{
+	{selector.Decimal{}, 5, 0, "1", 1},
+	{selector.Decimal{}, 12, 11, "012", 3},
+	{selector.HomeRow, 8, 7, ";", 1},
+	{selector.HomeRow, 9, 0, "aa", 2},
+	{selector.HomeRow, 64, 63, ";;", 2},
+	{selector.HomeRow, 65, 9, "ass", 3},
+}
+
+func TestEncodings(t *testing.T) {
+	for _, testCase := range encodingTestCases {
+		t.Logf("Testing index %d of %d with '%s'.", testCase.index, testCase.count, testCase.encoding)
+		e := testCase.encoding
+		if s := e.FromIndex(testCase.index, testCase.count); s != testCase.selector {
+			t.Errorf("Got selector '%s', expected '%s'.", s, testCase.selector)
+		}
+		if !e.IsComplete(testCase.selector, testCase.count) {
+			t.Errorf("Selector is not complete.")
+		}
+		if i, ok := e.ToIndex(testCase.selector, testCase.count); !ok || i != testCase.index {
+			t.Errorf("Got index %d (%v), expected %d.", i, ok, testCase.index)
+		}
+		if w := e.Width(testCase.count); w != testCase.width {
+			t.Errorf("Got width %d, expected %d.", w, testCase.width)
+		}
+	}
+}
+
+func TestAlphabetOutOfRange(t *testing.T) {
+	if _, ok := selector.HomeRow.ToIndex("s;", 10); ok {
+		t.Errorf("Selector beyond count was accepted.")
+	}
+}
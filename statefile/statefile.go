@@ -0,0 +1,127 @@
+// Package statefile implements files of lines, that keep state across
+// sessions, like visited links or reading positions.
+//
+// Lines are only ever appended. Appending a single line with O_APPEND is
+// atomic, so several processes may append to the same file at once. When
+// the file grows beyond its maximum size, it is compacted to the most
+// recent lines, which are written to a temporary file, that then
+// replaces the file by an atomic rename. Lines, that another process
+// appends during compaction, may be lost.
+package statefile
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+)
+
+// DefaultMaxSize is the size in bytes, that a file may grow to before it
+// is compacted.
+const DefaultMaxSize = 1 << 20
+
+// A File is a file of lines, that is kept across sessions.
+type File struct {
+	Path string
+
+	// MaxSize is the size in bytes, that the file may grow to before it
+	// is compacted to half of it.
+	MaxSize int64
+
+	// Key returns the key of a line. When compacting, only the most
+	// recent line of each key is kept. If Key is nil, the line itself is
+	// the key.
+	Key func(line string) string
+}
+
+// New returns a File at path with DefaultMaxSize.
+func New(path string, key func(line string) string) File {
+	return File{Path: path, MaxSize: DefaultMaxSize, Key: key}
+}
+
+// Lines returns the lines of f in the order they were appended. A
+// missing file contains no lines.
+func (f File) Lines() ([]string, error) {
+	file, err := os.Open(f.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	lines := make([]string, 0)
+	scanner := bufio.NewScanner(file)
+	if f.MaxSize > bufio.MaxScanTokenSize {
+		scanner.Buffer(nil, int(f.MaxSize))
+	}
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// Append appends line, which must not contain line breaks, to f. The
+// file and its directory are created, if necessary.
+func (f File) Append(line string) error {
+	if err := os.MkdirAll(filepath.Dir(f.Path), 0700); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(f.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err = file.WriteString(line + "\n"); err != nil {
+		file.Close()
+		return err
+	}
+	info, err := file.Stat()
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil || info.Size() <= f.MaxSize {
+		return err
+	}
+	return f.compact()
+}
+
+// compact replaces f with a file, that only contains the most recent
+// line of each key, taking up at most half of f.MaxSize.
+func (f File) compact() error {
+	lines, err := f.Lines()
+	if err != nil {
+		return err
+	}
+	kept, seen, size := make([]string, 0), make(map[string]bool), int64(0)
+	for i := len(lines) - 1; i >= 0; i-- {
+		key := lines[i]
+		if f.Key != nil {
+			key = f.Key(lines[i])
+		}
+		if seen[key] {
+			continue
+		}
+		if size += int64(len(lines[i]) + 1); size > f.MaxSize/2 {
+			break
+		}
+		seen[key] = true
+		kept = append(kept, lines[i])
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(f.Path), filepath.Base(f.Path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // Fails harmlessly after the rename.
+	w := bufio.NewWriter(tmp)
+	for i := len(kept) - 1; i >= 0; i-- {
+		w.WriteString(kept[i] + "\n")
+	}
+	if err = w.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.Path)
+}
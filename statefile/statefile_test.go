@@ -0,0 +1,30 @@
+package statefile_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/codesoap/gmir/statefile"
+)
+
+func TestCompactionByKey(t *testing.T) {
+	key := func(line string) string { return strings.Fields(line)[0] }
+	f := statefile.New(filepath.Join(t.TempDir(), "state"), key)
+	f.MaxSize = 20
+	for i := 0; i < 6; i++ {
+		if err := f.Append(fmt.Sprintf("%c %d", 'a'+i%2, i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lines, err := f.Lines()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"a 4", "b 5"}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Errorf("Got lines %q, expected %q.", lines, expected)
+	}
+}
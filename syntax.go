@@ -0,0 +1,60 @@
+package gmir
+
+import (
+	"github.com/codesoap/gmir/highlight"
+	"github.com/codesoap/gmir/parser"
+	"github.com/gdamore/tcell/v2"
+)
+
+// syntaxSpans tokenizes the preformatted blocks of doc, whose alt text
+// names a language with a registered highlighter. Blocks, that end
+// before the line with index from, are skipped. The returned map
+// contains the spans by index in doc.Lines().
+func syntaxSpans(doc parser.Document, from int) map[int][]highlight.Span {
+	spans := make(map[int][]highlight.Span)
+	line := 0 // The index in doc.Lines() of the first line of node.
+	for _, node := range doc.Nodes {
+		block, isBlock := node.(parser.PreformattedBlock)
+		if !isBlock {
+			line++
+			continue
+		}
+		altText := block.AltText()
+		if altText == "" {
+			line += len(block.Lines)
+			continue
+		}
+		line++ // The AltTextLine.
+		if parser.AltTextOnly {
+			continue
+		}
+		highlighter := highlight.Lookup(altText)
+		if highlighter != nil && line+len(block.Lines) > from {
+			text := make([]string, len(block.Lines))
+			for i, l := range block.Lines {
+				text[i] = l.Line.Text()
+			}
+			for i, lineSpans := range highlighter.Highlight(text) {
+				spans[line+i] = lineSpans
+			}
+		}
+		line += len(block.Lines)
+	}
+	return spans
+}
+
+func styleForKind(kind highlight.Kind) tcell.Style {
+	switch kind {
+	case highlight.Plain:
+		return stylePrefromatted
+	case highlight.Keyword:
+		return styleKeyword
+	case highlight.String:
+		return styleString
+	case highlight.Comment:
+		return styleComment
+	case highlight.Number:
+		return styleNumber
+	}
+	panic("unknown token kind")
+}
@@ -0,0 +1,49 @@
+package gmir
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/codesoap/gmir/parser"
+)
+
+// adjacentBlocks is a highlighted block, that is directly followed by a
+// block without alt text.
+const adjacentBlocks = "```go\nvar x = 1\n```\n```\nvar y = 2\n```\n"
+
+func TestSyntaxOfAdjacentBlocks(t *testing.T) {
+	v, err := NewView(strings.NewReader(adjacentBlocks), "")
+	if err != nil {
+		t.Fatalf("Could not parse input: %v", err)
+	}
+	checkAdjacentBlocks(t, v)
+}
+
+func TestSyntaxOfAdjacentBlocksWhileLoading(t *testing.T) {
+	stream := parser.NewStream(strings.NewReader(adjacentBlocks))
+	v := NewLoadingView("")
+	for {
+		node, ok := stream.Next()
+		if !ok {
+			break
+		}
+		// Every line is added on its own, like in small batches.
+		if err := v.AddLoaded(&EventLoad{nodes: []parser.LineNode{node}}); err != nil {
+			t.Fatalf("Could not add line: %v", err)
+		}
+	}
+	checkAdjacentBlocks(t, v)
+}
+
+func checkAdjacentBlocks(t *testing.T, v View) {
+	t.Helper()
+	if len(v.lines) != 3 {
+		t.Fatalf("Got %d lines, expected 3.", len(v.lines))
+	}
+	if len(v.syntax[1]) == 0 {
+		t.Errorf("The go block is not highlighted.")
+	}
+	if len(v.syntax[2]) != 0 {
+		t.Errorf("The block without alt text is highlighted.")
+	}
+}
@@ -0,0 +1,36 @@
+package gmir
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/codesoap/gmir/parser"
+	"github.com/codesoap/gmir/readline"
+)
+
+func TestUpdateTOCKeepsState(t *testing.T) {
+	doc := NewLoadingView("")
+	stream := parser.NewStream(strings.NewReader("# One\ntext\n## Two\n### Three\n"))
+	var nodes []parser.LineNode
+	for node, ok := stream.Next(); ok; node, ok = stream.Next() {
+		nodes = append(nodes, node)
+	}
+	if err := doc.AddLoaded(&EventLoad{nodes: nodes[:2]}); err != nil {
+		t.Fatalf("Could not add lines: %v", err)
+	}
+	toc := doc.TOCView()
+	toc.StartSearch(Search, &readline.Editor{})
+	toc.selector = "1"
+	toc.focus = 0
+
+	if err := doc.AddLoaded(&EventLoad{nodes: nodes[2:], done: true}); err != nil {
+		t.Fatalf("Could not add lines: %v", err)
+	}
+	doc.UpdateTOC(&toc)
+	if len(toc.lines) != 3 {
+		t.Errorf("Got %d headings, expected 3.", len(toc.lines))
+	}
+	if toc.Mode != Search || toc.Prompt == nil || toc.selector != "1" || toc.focus != 0 {
+		t.Errorf("The state of the table of contents was lost.")
+	}
+}
@@ -0,0 +1,60 @@
+// Package visited stores the URLs of visited links in a state file, so
+// that they are remembered across sessions. The file contains one URL
+// per line.
+package visited
+
+import (
+	"strings"
+
+	"github.com/codesoap/gmir/statefile"
+)
+
+// DefaultMaxSize is the size in bytes, that a store may grow to before
+// it is compacted.
+const DefaultMaxSize = statefile.DefaultMaxSize
+
+// A Store is a set of visited URLs, that is backed by a file.
+type Store struct {
+	file statefile.File // Has an empty path, if the store is only kept in memory.
+	urls map[string]bool
+
+	// MaxSize is the size in bytes, that the file may grow to before it
+	// is compacted to half of it.
+	MaxSize int64
+}
+
+// Open reads the store at path. A missing file is an empty store. If
+// path is empty, the store is only kept in memory.
+func Open(path string) (*Store, error) {
+	s := &Store{file: statefile.New(path, nil), urls: make(map[string]bool), MaxSize: DefaultMaxSize}
+	if path == "" {
+		return s, nil
+	}
+	urls, err := s.file.Lines()
+	if err != nil {
+		return nil, err
+	}
+	for _, url := range urls {
+		s.urls[url] = true
+	}
+	return s, nil
+}
+
+// Contains returns true, if url has been visited.
+func (s *Store) Contains(url string) bool {
+	return s.urls[url]
+}
+
+// Add marks url as visited and appends it to the file of s, unless it
+// is known to be visited already.
+func (s *Store) Add(url string) error {
+	if url == "" || strings.ContainsAny(url, "\r\n") || s.urls[url] {
+		return nil
+	}
+	s.urls[url] = true
+	if s.file.Path == "" {
+		return nil
+	}
+	s.file.MaxSize = s.MaxSize
+	return s.file.Append(url)
+}
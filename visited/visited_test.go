@@ -0,0 +1,82 @@
+package visited_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/codesoap/gmir/visited"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gmir", "visited")
+	s, err := visited.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = s.Add("gemini://example.org/"); err != nil {
+		t.Fatal(err)
+	}
+	reopened, err := visited.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reopened.Contains("gemini://example.org/") {
+		t.Errorf("Added URL is missing after reopening.")
+	}
+	if reopened.Contains("gemini://example.org/other") {
+		t.Errorf("Unknown URL is contained.")
+	}
+}
+
+func TestCompaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "visited")
+	s, err := visited.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.MaxSize = 100
+	for i := 0; i < 20; i++ {
+		if err = s.Add(fmt.Sprintf("gemini://x/%02d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() > s.MaxSize {
+		t.Errorf("Store has %d bytes, expected at most %d.", info.Size(), s.MaxSize)
+	}
+	reopened, err := visited.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reopened.Contains("gemini://x/19") {
+		t.Errorf("Most recent URL was dropped.")
+	}
+	if reopened.Contains("gemini://x/00") {
+		t.Errorf("Oldest URL was kept.")
+	}
+}
+
+func TestAddKnownURL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "visited")
+	s, err := visited.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if err = s.Add("gemini://example.org/"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "gemini://example.org/\n" {
+		t.Errorf("Got '%s', expected the URL once.", content)
+	}
+}